@@ -0,0 +1,218 @@
+package main
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ryanpdenoux/advent-of-code/solutions"
+)
+
+// Sources the binary was built from, hashed to tell when a solver changed
+//
+//go:embed solutions/*.go utils
+var sources embed.FS
+
+// Puzzles have at most two parts
+const maxParts = 2
+
+// Bumped whenever cachedAnswer changes shape, so entries written without a
+// field, like the reports of version 1, are solved again instead of used
+const cacheFormat = 2
+
+// Flags of the runner itself, every other flag set on the command line
+// tweaks a solver and becomes part of its version
+var runnerFlags = map[string]bool{
+	"day":        true,
+	"year":       true,
+	"debug":      true,
+	"quiet":      true,
+	"example":    true,
+	"skip-check": true,
+	"all":        true,
+	"no-cache":   true,
+	"json":       true,
+	"input":      true,
+	"part":       true,
+	"addr":       true,
+	"timeout":    true,
+	"max-solves": true,
+	"max-input":  true,
+}
+
+// Solver flags naming a file, the file's contents are part of the solver
+// version so editing it invalidates the cached answers
+var fileFlags = map[string]bool{
+	"cube-config": true,
+}
+
+var dayFile = regexp.MustCompile(`^day\d+\.go$`)
+
+type cachedAnswer struct {
+	solutions.Answer
+	Input  string
+	Solved time.Time
+}
+
+// Answers of earlier runs stored as JSON in the user's cache directory
+type resultCache struct {
+	path    string
+	Entries map[string]cachedAnswer
+}
+
+func cachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aoc", "results.json"), nil
+}
+
+// Loads the cache, a missing file is an empty cache
+func loadCache(path string) (*resultCache, error) {
+	cache := &resultCache{path: path, Entries: map[string]cachedAnswer{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("corrupt cache %v: %v", path, err)
+	}
+
+	return cache, nil
+}
+
+func (c *resultCache) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	// write then rename so an interrupted run never leaves half a file
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+func cacheKey(year, day, part int, input, solver string) string {
+	return fmt.Sprintf("v%d/%d/%d/%d/%s/%s", cacheFormat, year, day, part, input, solver)
+}
+
+// Cached answers of a day, nil if none of its parts were stored. A nil
+// cache has nothing stored
+func (c *resultCache) lookup(year, day int, input, solver string) []cachedAnswer {
+	if c == nil {
+		return nil
+	}
+
+	answers := []cachedAnswer{}
+
+	for part := 1; part <= maxParts; part++ {
+		if answer, ok := c.Entries[cacheKey(year, day, part, input, solver)]; ok {
+			answers = append(answers, answer)
+		}
+	}
+	if len(answers) == 0 {
+		return nil
+	}
+
+	return answers
+}
+
+func (c *resultCache) store(year, day int, input, solver string, answers []solutions.Answer) {
+	if c == nil {
+		return
+	}
+	for _, answer := range answers {
+		c.Entries[cacheKey(year, day, answer.Part, input, solver)] = cachedAnswer{
+			Answer: answer,
+			Input:  input,
+			Solved: time.Now(),
+		}
+	}
+}
+
+// Hash of the day's source, the shared solution files, utils, any solver
+// flags and the files they name, changing one of them invalidates the day's
+// cached answers. Tests are embedded too but do not change what a solver does
+func solverVersion(day int) (string, error) {
+	hash := sha256.New()
+
+	add := func(path string) error {
+		data, err := sources.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s %d\n", path, len(data))
+		hash.Write(data)
+		return nil
+	}
+
+	if err := add(fmt.Sprintf("solutions/day%d.go", day)); err != nil {
+		return "", err
+	}
+
+	err := fs.WalkDir(sources, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || dayFile.MatchString(entry.Name()) || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		return add(path)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(hash, "flags %s\n", solverFlags())
+
+	flag.Visit(func(f *flag.Flag) {
+		if err != nil || !fileFlags[f.Name] || f.Value.String() == "" {
+			return
+		}
+		var data []byte
+		data, err = os.ReadFile(f.Value.String())
+		fmt.Fprintf(hash, "file %s %d\n", f.Name, len(data))
+		hash.Write(data)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Solver flags set on the command line as name=value, in lexical order
+func solverFlags() string {
+	return strings.Join(solverArgs(), " ")
+}
+
+// Solver flags set on the command line as arguments to pass on
+func solverArgs() []string {
+	args := []string{}
+
+	flag.Visit(func(f *flag.Flag) {
+		if !runnerFlags[f.Name] {
+			args = append(args, fmt.Sprintf("-%s=%s", f.Name, f.Value))
+		}
+	})
+
+	return args
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+)
+
+// Matches below this are not worth reporting
+const identifyThreshold = 0.1
+
+type dayScore struct {
+	day   int
+	score float64
+}
+
+// Scores a file against the signature of every registered day and prints
+// the best matches
+func identify(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Could not read %v: %v", path, err)
+	}
+
+	scores := scoreDays(data)
+	if len(scores) == 0 {
+		fmt.Printf("%s does not look like the input of any day\n", path)
+		return
+	}
+
+	fmt.Printf("Best matches for %s:\n", path)
+	for i, match := range scores {
+		if i == 3 {
+			break
+		}
+		fmt.Printf("  day %-2d %3.0f%%\n", match.day, match.score*100)
+	}
+}
+
+// Days whose signature fits data, best first
+func scoreDays(data []byte) []dayScore {
+	scores := []dayScore{}
+
+	for day, solver := range solutionMap {
+		score := solver.Signature.Score(data)
+		if score >= identifyThreshold {
+			scores = append(scores, dayScore{day, score})
+		}
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].score != scores[j].score {
+			return scores[i].score > scores[j].score
+		}
+		return scores[i].day < scores[j].day
+	})
+	return scores
+}
@@ -1,27 +1,36 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"flag"
 	"fmt"
-	"log"
+	"io"
 	"log/slog"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/ryanpdenoux/advent-of-code/utils"
 	"github.com/ryanpdenoux/advent-of-code/solutions"
 )
 
+// Year the registered solutions belong to
+const solutionYear = 2023
+
 // register solution funcs
-var solutionMap = map[int]func(*os.File){
-	1: solutions.Day1,
-	2: solutions.Day2,
-	3: solutions.Day3,
-	4: solutions.Day4,
-	5: solutions.Day5,
-	6: solutions.Day6,
-	7: solutions.Day7,
-	8: solutions.Day8,
-	9: solutions.Day9,
+var solutionMap = map[int]solutions.Solver{
+	1: {Solve: solutions.Day1, Signature: solutions.Day1Signature},
+	2: {Solve: solutions.Day2, Signature: solutions.Day2Signature},
+	3: {Solve: solutions.Day3, Signature: solutions.Day3Signature},
+	4: {Solve: solutions.Day4, Signature: solutions.Day4Signature},
+	5: {Solve: solutions.Day5, Signature: solutions.Day5Signature},
+	6: {Solve: solutions.Day6, Signature: solutions.Day6Signature},
+	7: {Solve: solutions.Day7, Signature: solutions.Day7Signature},
+	8: {Solve: solutions.Day8, Signature: solutions.Day8Signature},
+	9: {Solve: solutions.Day9, Signature: solutions.Day9Signature},
 }
 
 var (
@@ -30,7 +39,7 @@ var (
 		"day[n] to select for solution",
 	)
 	year = flag.Int("year",
-		2023,
+		solutionYear,
 		"Year that solution exists",
 	)
 	debug = flag.Bool("debug",
@@ -41,8 +50,96 @@ var (
 		false,
 		"Turn off logging output",
 	)
+	skipCheck = flag.Bool("skip-check",
+		false,
+		"Solve even if the input does not look like the day's input",
+	)
+	all = flag.Bool("all",
+		false,
+		"Solve every registered day, days with cached answers are not solved again",
+	)
+	noCache = flag.Bool("no-cache",
+		false,
+		"Solve even if answers for the same input and solver are cached",
+	)
+	jsonOutput = flag.Bool("json",
+		false,
+		"Print results as JSON lines, logs go to stderr",
+	)
+	inputPath = flag.String("input",
+		"",
+		"Read the input from this file instead, - reads stdin",
+	)
+	part = flag.Int("part",
+		0,
+		"Only report the answer to this part",
+	)
+	example exampleFlag
 )
 
+func init() {
+	flag.Var(&example,
+		"example",
+		"Use the embedded example input instead, -example 2 picks the second one",
+	)
+}
+
+// Optional numbered flag: "-example" means 1, "-example=2" or "-example 2"
+// pick another example
+type exampleFlag int
+
+func (e *exampleFlag) String() string {
+	return strconv.Itoa(int(*e))
+}
+
+func (e *exampleFlag) Set(value string) error {
+	if value == "true" {
+		*e = 1
+		return nil
+	}
+	if value == "false" {
+		*e = 0
+		return nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return fmt.Errorf("example must be a positive number")
+	}
+	*e = exampleFlag(n)
+	return nil
+}
+
+func (e *exampleFlag) IsBoolFlag() bool {
+	return true
+}
+
+// Parses flags and returns the positional arguments. Flags may follow a
+// positional argument, and bool style flags do not consume the next argument
+// so a number straight after -example is picked up here
+func parseFlags(args []string) ([]string, error) {
+	positional := []string{}
+
+	for {
+		if err := flag.CommandLine.Parse(args); err != nil {
+			return nil, err
+		}
+
+		rest := flag.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if _, err := strconv.Atoi(rest[0]); err == nil && example > 0 {
+			if err := example.Set(rest[0]); err != nil {
+				return nil, fmt.Errorf("invalid value %q for flag -example: %v", rest[0], err)
+			}
+		} else {
+			positional = append(positional, rest[0])
+		}
+		args = rest[1:]
+	}
+}
+
 func pickDay() (int) {
 	var day int
 
@@ -52,7 +149,7 @@ func pickDay() (int) {
 	return day
 }
 
-func setupLogging(debug, quiet bool) {
+func setupLogging(debug, quiet bool, out io.Writer) {
 	opts := &slog.HandlerOptions{}
 	if debug {
 		opts.Level = slog.LevelDebug
@@ -60,26 +157,249 @@ func setupLogging(debug, quiet bool) {
 	if quiet {
 		opts.Level = slog.LevelError
 	}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, opts))
+	logger := slog.New(slog.NewTextHandler(out, opts))
 	slog.SetDefault(logger)
 }
 
-func main() {
-	flag.Parse()
+// Opens the real input for a day, one of its embedded examples or the file
+// given with -input
+func openInput(day int) (io.ReadCloser, error) {
+	if *inputPath == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	if *inputPath != "" {
+		return os.Open(*inputPath)
+	}
+	if example > 0 {
+		return solutions.Example(day, int(example))
+	}
+
+	dataPath := utils.BuildDataPath(day)
+	file, err := os.Open(dataPath)
+	if err != nil {
+		return nil, fmt.Errorf("Could not open file %v: %v", dataPath, err)
+	}
+	return file, nil
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: aoc [run] [flags]\n")
+	fmt.Fprintf(out, "       aoc identify FILE\n")
+	fmt.Fprintf(out, "       aoc watch -day D [flags]\n")
+	fmt.Fprintf(out, "       aoc serve [-addr :8080] [flags]\n\n")
+	fmt.Fprintf(out, "Commands:\n")
+	fmt.Fprintf(out, "  run       solve a day (default)\n")
+	fmt.Fprintf(out, "  identify  guess which day an input file belongs to\n")
+	fmt.Fprintf(out, "  watch     rebuild and rerun a day whenever its files change\n")
+	fmt.Fprintf(out, "  serve     solve inputs posted to /solve/{year}/{day}/{part}\n\n")
+	fmt.Fprintf(out, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+// Splits off the subcommand, plain "aoc -day 5" keeps working as run
+func parseCommand(args []string) (string, []string) {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		return args[0], args[1:]
+	}
+	return "run", args
+}
+
+func run() {
+	cache := openCache()
+
+	if *all {
+		if *inputPath != "" {
+			fmt.Fprintln(os.Stderr, "-input can not be used with -all")
+			os.Exit(2)
+		}
+
+		days := []int{}
+		for day := range solutionMap {
+			days = append(days, day)
+		}
+		sort.Ints(days)
 
-	setupLogging(*debug, *quiet)
+		failed := false
+		for _, day := range days {
+			if !*jsonOutput {
+				fmt.Printf("Day %d\n", day)
+			}
+			result, err := solveDay(day, cache)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Day %d: %v\n", day, err)
+				failed = true
+				continue
+			}
+			printResult(result)
+		}
+
+		saveCache(cache)
+		if failed {
+			os.Exit(1)
+		}
+		return
+	}
 
 	if *day == 0 {
 		*day = pickDay()
 	}
 
-	solution_func := solutionMap[*day]
-	dataPath := utils.BuildDataPath(*day)
-	file, err := os.Open(dataPath)
+	// not logged so -quiet still shows why nothing was solved
+	result, err := solveDay(*day, cache)
+	saveCache(cache)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	printResult(result)
+}
+
+// Checks the input, then takes the cached answers when the input and solver
+// are unchanged or solves the day otherwise
+func solveDay(day int, cache *resultCache) (*dayResult, error) {
+	solver, ok := solutionMap[day]
+	if !ok {
+		return nil, fmt.Errorf("No solution registered for day %d", day)
+	}
+
+	data, err := readInput(day)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := solver.Signature.Check(data); err != nil {
+		if !*skipCheck {
+			return nil, fmt.Errorf("Input does not look like day %d input: %v (use -skip-check to solve it anyway)", day, err)
+		}
+		slog.Warn("Input does not look like the day's input", "day", day, "err", err)
+	}
+
+	result := &dayResult{Year: *year, Day: day, Input: inputHash(data)}
+	if *inputPath == "" {
+		result.Example = int(example)
+	}
+	version, err := solverVersion(day)
+	if err != nil {
+		slog.Warn("Could not hash solver source, not caching", "day", day, "err", err)
+		cache = nil
+	}
+
+	if cached := cache.lookup(*year, day, result.Input, version); cached != nil {
+		for _, answer := range cached {
+			result.add(answer.Answer, true)
+		}
+	} else {
+		answers := solver.Solve(bytes.NewReader(data))
+		for _, answer := range answers {
+			result.add(answer, false)
+		}
+		cache.store(*year, day, result.Input, version, answers)
+	}
+
+	// the known answers hold for the default solvers only
+	if result.Example > 0 && solverFlags() == "" {
+		result.expect(solutions.ExampleAnswers(day, result.Example))
+	}
+	if *part > 0 && !result.only(*part) {
+		return nil, fmt.Errorf("Day %d has no answer for part %d", day, *part)
+	}
+	return result, nil
+}
+
+// Cache of earlier answers, nil with -no-cache or when it can not be read
+func openCache() *resultCache {
+	if *noCache {
+		return nil
+	}
+
+	path, err := cachePath()
+	if err != nil {
+		slog.Warn("No cache directory, not caching", "err", err)
+		return nil
+	}
+	cache, err := loadCache(path)
+	if err != nil {
+		slog.Warn("Could not load cache, not caching", "err", err)
+		return nil
+	}
+
+	return cache
+}
+
+func saveCache(cache *resultCache) {
+	if cache == nil {
+		return
+	}
+	if err := cache.save(); err != nil {
+		slog.Warn("Could not save cache", "err", err)
+	}
+}
+
+// Reads the whole input up front so it can be checked and hashed
+func readInput(day int) ([]byte, error) {
+	file, err := openInput(day)
 	if err != nil {
-		log.Fatalf("Could not open file %v: %v", dataPath, err)
+		return nil, err
 	}
 	defer file.Close()
 
-	solution_func(file)
+	return io.ReadAll(file)
+}
+
+func inputHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func main() {
+	flag.Usage = usage
+	command, args := parseCommand(os.Args[1:])
+	args, err := parseFlags(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		usage()
+		os.Exit(2)
+	}
+
+	logOutput := io.Writer(os.Stdout)
+	if *jsonOutput {
+		logOutput = os.Stderr
+	}
+	setupLogging(*debug, *quiet, logOutput)
+
+	switch command {
+	case "run":
+		if len(args) > 0 {
+			fmt.Fprintf(os.Stderr, "run takes no arguments, got %v\n\n", args)
+			usage()
+			os.Exit(2)
+		}
+		run()
+	case "identify":
+		if len(args) != 1 {
+			fmt.Fprintf(os.Stderr, "identify takes exactly one FILE\n\n")
+			usage()
+			os.Exit(2)
+		}
+		identify(args[0])
+	case "watch":
+		if *day == 0 || len(args) > 0 {
+			fmt.Fprintf(os.Stderr, "watch takes a -day and no arguments\n\n")
+			usage()
+			os.Exit(2)
+		}
+		watch(*day)
+	case "serve":
+		if len(args) > 0 {
+			fmt.Fprintf(os.Stderr, "serve takes no arguments, got %v\n\n", args)
+			usage()
+			os.Exit(2)
+		}
+		serve()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", command)
+		usage()
+		os.Exit(2)
+	}
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ryanpdenoux/advent-of-code/solutions"
+)
+
+// Answers of one run of a day, printed as text or as a JSON line
+type dayResult struct {
+	Year    int            `json:"year"`
+	Day     int            `json:"day"`
+	Example int            `json:"example,omitempty"`
+	Input   string         `json:"input_sha256"`
+	Answers []resultAnswer `json:"answers"`
+}
+
+type resultAnswer struct {
+	Part     int      `json:"part"`
+	Label    string   `json:"label"`
+	Value    string   `json:"value"`
+	Expected string   `json:"expected,omitempty"`
+	Cached   bool     `json:"cached"`
+	Report   []string `json:"report,omitempty"`
+}
+
+func (r *dayResult) add(answer solutions.Answer, cached bool) {
+	r.Answers = append(r.Answers, resultAnswer{
+		Part:   answer.Part,
+		Label:  answer.Label,
+		Value:  answer.Value,
+		Cached: cached,
+		Report: answer.Report,
+	})
+}
+
+// Attaches the known answers of an example to the matching parts
+func (r *dayResult) expect(expected map[int]string) {
+	for i := range r.Answers {
+		r.Answers[i].Expected = expected[r.Answers[i].Part]
+	}
+}
+
+// Drops the answers to other parts, false if none is left
+func (r *dayResult) only(part int) bool {
+	answers := []resultAnswer{}
+	for _, answer := range r.Answers {
+		if answer.Part == part {
+			answers = append(answers, answer)
+		}
+	}
+
+	r.Answers = answers
+	return len(answers) > 0
+}
+
+func (a resultAnswer) Wrong() bool {
+	return a.Expected != "" && a.Expected != a.Value
+}
+
+func printResult(result *dayResult) {
+	if *jsonOutput {
+		json.NewEncoder(os.Stdout).Encode(result)
+		return
+	}
+
+	for _, answer := range result.Answers {
+		line := fmt.Sprintf("%s: %s", answer.Label, answer.Value)
+		if answer.Cached {
+			line += " (cached)"
+		}
+		if answer.Wrong() {
+			line += fmt.Sprintf(" (expected %s)", answer.Expected)
+		}
+		fmt.Println(line)
+		for _, report := range answer.Report {
+			fmt.Println(report)
+		}
+	}
+	fmt.Printf("Input sha256: %s\n", result.Input)
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanpdenoux/advent-of-code/solutions"
+)
+
+var (
+	addr = flag.String("addr",
+		":8080",
+		"Address the serve command listens on",
+	)
+	solveTimeout = flag.Duration("timeout",
+		30*time.Second,
+		"Longest a served solve may wait and run for",
+	)
+	maxSolves = flag.Int("max-solves",
+		runtime.NumCPU(),
+		"Solves the serve command runs at once, others wait for a slot",
+	)
+	maxInput = flag.Int64("max-input",
+		1<<20,
+		"Largest input body in bytes the serve command accepts",
+	)
+)
+
+// Serves the solvers over HTTP until interrupted. Every solve runs this
+// binary again as `run -input -` so a solver calling log.Fatal or leaking
+// state can not take the server down, and a timeout really stops it
+func serve() {
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Could not find own binary: %v", err)
+	}
+
+	s := &solveServer{
+		executable: executable,
+		slots:      make(chan struct{}, *maxSolves),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/solvers", s.handleSolvers)
+	mux.HandleFunc("/solve/", s.handleSolve)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), *solveTimeout)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	slog.Info("Serving solvers", "addr", *addr, "timeout", *solveTimeout, "max-solves", *maxSolves)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+type solveServer struct {
+	executable string
+	// one token per running solve
+	slots chan struct{}
+}
+
+type solverInfo struct {
+	Year      int    `json:"year"`
+	Day       int    `json:"day"`
+	Examples  int    `json:"examples"`
+	Prefix    string `json:"prefix,omitempty"`
+	LineShape string `json:"line_shape,omitempty"`
+}
+
+type solveResponse struct {
+	Year   int      `json:"year"`
+	Day    int      `json:"day"`
+	Part   int      `json:"part"`
+	Label  string   `json:"label,omitempty"`
+	Answer string   `json:"answer,omitempty"`
+	Report []string `json:"report,omitempty"`
+	Input  string   `json:"input_sha256,omitempty"`
+	TookMs float64  `json:"took_ms"`
+	Logs   []string `json:"logs"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// GET /solvers lists the registered days
+func (s *solveServer) handleSolvers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+
+	days := []int{}
+	for day := range solutionMap {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+
+	solvers := []solverInfo{}
+	for _, day := range days {
+		info := solverInfo{
+			Year:     solutionYear,
+			Day:      day,
+			Examples: solutions.ExampleCount(day),
+			Prefix:   solutionMap[day].Signature.Prefix,
+		}
+		if line := solutionMap[day].Signature.Line; line != nil {
+			info.LineShape = line.String()
+		}
+		solvers = append(solvers, info)
+	}
+
+	writeJSON(w, http.StatusOK, solvers)
+}
+
+// POST /solve/{year}/{day}/{part} solves the request body as input
+func (s *solveServer) handleSolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+
+	year, day, part, err := parseSolvePath(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if _, ok := solutionMap[day]; !ok || year != solutionYear {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no solver for %d day %d", year, day))
+		return
+	}
+	if part < 1 || part > maxParts {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no part %d", part))
+		return
+	}
+
+	input, err := io.ReadAll(http.MaxBytesReader(w, r.Body, *maxInput))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), *solveTimeout)
+	defer cancel()
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		writeError(w, http.StatusServiceUnavailable, "too many solves running, try again later")
+		return
+	}
+
+	response := s.solve(ctx, day, part, input)
+	status := http.StatusOK
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+		response.Error = fmt.Sprintf("solve took longer than %v", *solveTimeout)
+	case response.Error != "":
+		status = http.StatusUnprocessableEntity
+	}
+
+	slog.Info("Solved", "day", day, "part", part, "status", status, "took_ms", response.TookMs)
+	writeJSON(w, status, response)
+}
+
+// Runs the solver in a child process, its stderr becomes the logs. Solver
+// flags and -skip-check given to serve are passed on
+func (s *solveServer) solve(ctx context.Context, day, part int, input []byte) *solveResponse {
+	response := &solveResponse{Year: solutionYear, Day: day, Part: part}
+
+	args := []string{"run",
+		"-day", strconv.Itoa(day),
+		"-part", strconv.Itoa(part),
+		"-input", "-",
+		"-json",
+		"-no-cache",
+	}
+	if *skipCheck {
+		args = append(args, "-skip-check")
+	}
+	args = append(args, solverArgs()...)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.executable, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	response.TookMs = float64(time.Since(start).Microseconds()) / 1000
+	response.Logs = logLines(stderr.String())
+
+	if err != nil {
+		response.Error = fmt.Sprintf("%v: %s", err, lastLine(stderr.String()))
+		return response
+	}
+
+	result := &dayResult{}
+	if err := json.Unmarshal(stdout.Bytes(), result); err != nil {
+		response.Error = fmt.Sprintf("unreadable result: %v", err)
+		return response
+	}
+	response.Input = result.Input
+	response.Label = result.Answers[0].Label
+	response.Answer = result.Answers[0].Value
+	response.Report = result.Answers[0].Report
+
+	return response
+}
+
+// Splits /solve/{year}/{day}/{part}
+func parseSolvePath(path string) (year, day, part int, err error) {
+	fields := strings.Split(strings.Trim(strings.TrimPrefix(path, "/solve/"), "/"), "/")
+	if len(fields) != 3 {
+		return 0, 0, 0, fmt.Errorf("expected /solve/{year}/{day}/{part}, got %s", path)
+	}
+
+	numbers := make([]int, len(fields))
+	for i, field := range fields {
+		numbers[i], err = strconv.Atoi(field)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("%q is not a number", field)
+		}
+	}
+
+	return numbers[0], numbers[1], numbers[2], nil
+}
+
+func logLines(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(text, "\n")
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
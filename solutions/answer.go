@@ -0,0 +1,29 @@
+package solutions
+
+import (
+	"fmt"
+	"io"
+)
+
+// Solution of a day along with the shape of input it expects
+type Solver struct {
+	Solve     func(io.Reader) []Answer
+	Signature Signature
+}
+
+// Answer to one part of a day, Report holds optional extra lines about how
+// it was found
+type Answer struct {
+	Part   int
+	Label  string
+	Value  string
+	Report []string
+}
+
+func newAnswer(part int, label string, value any) Answer {
+	return Answer{Part: part, Label: label, Value: fmt.Sprint(value)}
+}
+
+func (a Answer) String() string {
+	return fmt.Sprintf("%s: %s", a.Label, a.Value)
+}
@@ -3,43 +3,90 @@ package solutions
 import (
 	"bufio"
 	"fmt"
-	"log"
-	"os"
-	"strconv"
-	"unicode/utf8"
+	"io"
+	"log/slog"
+	"regexp"
+
+	"github.com/ryanpdenoux/advent-of-code/utils/strsearch"
 )
 
-func Day1(file *os.File) {
-	sum, err := sumCalibrationValues(file)
-	if err != nil {
-		log.Fatal(err)
+// Lines of letters mixed with digits
+var Day1Signature = Signature{
+	Line: regexp.MustCompile(`^[a-z0-9]+$`),
+}
+
+func Day1(file io.Reader) []Answer {
+	answers := []Answer{}
+
+	lines := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
 	}
 
-	fmt.Printf("Sum of values: %d\n", sum)
+	for part := 1; part <= len(calibrationDigits); part++ {
+		sum, err := sumCalibrationValues(lines, calibrationDigits[part])
+		if err != nil {
+			// part 2 examples contain lines without any numeric digit
+			slog.Error("Could not calibrate", "part", part, "err", err)
+			continue
+		}
+
+		answers = append(answers, newAnswer(part, fmt.Sprintf("Sum of values (part %d)", part), sum))
+	}
+
+	return answers
 }
 
-var parsingFuncs = []func([]rune, int) (int, bool){
-	strToInt,
+// Dictionary of digit spellings and the value each one stands for
+type digitMatcher struct {
+	search *strsearch.Automaton[rune]
+	values []int
 }
 
-func strToInt(chars []rune, i int) (int, bool) {
-	num, err := strconv.Atoi(string(chars[i]))
-	if err != nil {
-		return 0, false
+func newDigitMatcher(dictionaries ...map[string]int) *digitMatcher {
+	m := &digitMatcher{}
+	words := []string{}
+
+	for _, dictionary := range dictionaries {
+		for word, value := range dictionary {
+			words = append(words, word)
+			m.values = append(m.values, value)
+		}
 	}
+	m.search = strsearch.NewRunes(words...)
 
-	return num, true
+	return m
 }
 
-// func completeTrie(chars []rune, i int) (int, bool) {}
+var numericDigits = map[string]int{
+	"0": 0, "1": 1, "2": 2, "3": 3, "4": 4,
+	"5": 5, "6": 6, "7": 7, "8": 8, "9": 9,
+}
 
-func sumCalibrationValues(file *os.File) (int, error) {
-	scanner := bufio.NewScanner(file)
+var spelledDigits = map[string]int{
+	"one":   1,
+	"two":   2,
+	"three": 3,
+	"four":  4,
+	"five":  5,
+	"six":   6,
+	"seven": 7,
+	"eight": 8,
+	"nine":  9,
+}
+
+// digit recognisers used by each part of the puzzle
+var calibrationDigits = map[int]*digitMatcher{
+	1: newDigitMatcher(numericDigits),
+	2: newDigitMatcher(numericDigits, spelledDigits),
+}
+
+func sumCalibrationValues(lines []string, digits *digitMatcher) (int, error) {
 	var sum int
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		value, err := constructValue(line)
+	for _, line := range lines {
+		value, err := constructValue(line, digits)
 		if err != nil {
 			return 0, fmt.Errorf("Something busted: %v", err)
 		}
@@ -49,39 +96,17 @@ func sumCalibrationValues(file *os.File) (int, error) {
 	return sum, nil
 }
 
-func constructValue(line string) (int, error) {
-	first, ok := pickFirstDigit(line)
+// The first digit is the match starting earliest and the last digit the one
+// starting latest, so overlapping words like "eightwo" count from both ends
+func constructValue(line string, digits *digitMatcher) (int, error) {
+	chars := []rune(line)
+
+	first, ok := digits.search.First(chars)
 	if ok != true {
 		return 0, fmt.Errorf("No digits in string: %v", line)
 	}
 
-	last, _ := pickFirstDigit(reverseString(line))
-	value := ((10 * first) + last)
+	last, _ := digits.search.Last(chars)
+	value := ((10 * digits.values[first.Pattern]) + digits.values[last.Pattern])
 	return value, nil
 }
-
-func reverseString(input string) string {
-	r := make([]byte, 0, len(input))
-	for len(input) > 0 {
-		_, n := utf8.DecodeLastRuneInString(input)
-		i := len(input) - n
-		r = append(r, input[i:]...)
-		input = input[:i]
-	}
-	return string(r)
-}
-
-func pickFirstDigit(input string) (int, bool) {
-	chars := []rune(input)
-
-	for pos := range(chars) {
-		for _, parsingFn := range(parsingFuncs) {
-			num, ok := parsingFn(chars, pos)
-			if ok == true {
-				return num, ok
-			}
-		}
-	}
-
-	return 0, false
-}
@@ -2,32 +2,72 @@ package solutions
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"io"
+	"log"
 	"log/slog"
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 
-	"github.com/ryanpdenoux/advent-of-code/utils"
+	"github.com/ryanpdenoux/advent-of-code/utils/lex"
 )
 
-func Day2(file *os.File) {
+var (
+	cubeLimits = flag.String(
+		"cube-limits",
+		"12 red, 13 green, 14 blue",
+		"cubes of each colour in the bag for Day2",
+	)
+	cubeConfig = flag.String(
+		"cube-config",
+		"",
+		"file listing cubes of each colour for Day2, overrides -cube-limits",
+	)
+	cubeStrict = flag.Bool(
+		"cube-strict",
+		false,
+		"fail on colours missing from the Day2 cube limits",
+	)
+)
+
+// "Game N: 3 blue, 4 red; ..."
+var Day2Signature = Signature{
+	Prefix: "Game ",
+	Line:   regexp.MustCompile(`^Game \d+: \d+ \w+`),
+}
+
+func Day2(file io.Reader) []Answer {
 	var sum int = 0
+	var power int = 0
 	var id int = 1
 
 	scanner := bufio.NewScanner(file)
-	current_rules := Rules{12, 13, 14}
+	current_rules, err := loadCubeRules()
+	if err != nil {
+		log.Fatalf("Could not load cube limits: %v", err)
+	}
 
 	for scanner.Scan() {
 		line := scanner.Text()
 		game := newGame(id, line)
-		game.solveGame(current_rules)
+		if err := game.solveGame(current_rules); err != nil {
+			log.Fatalf("Game %d: %v", id, err)
+		}
 		if game.Valid {
 			sum += id
 		}
+		power += current_rules.Power(game.Minimum)
 		id++
 	}
 
-	fmt.Printf("Sum of game ids: %d\n", sum)
+	return []Answer{
+		newAnswer(1, "Sum of game ids", sum),
+		newAnswer(2, "Sum of minimum set powers", power),
+	}
 }
 
 type parser interface {
@@ -35,78 +75,151 @@ type parser interface {
 }
 
 const (
-	ILLEGAL = "ILLEGAL"
-	EOL     = "EOL"
-	GAME    = "GAME"
-	ID      = "ID"
-	COLON   = "COLON"
-	COMMA   = "COMMA"
-	COMMENT = "COMMENT"
-	SEMIC   = "SEMIC"
-	RED     = "RED"
-	GREEN   = "GREEN"
-	BLUE    = "BLUE"
-	VALUE   = "VALUE"
+	ILLEGAL lex.TokenType = lex.ILLEGAL
+	EOL     lex.TokenType = lex.EOF
+	GAME    lex.TokenType = "GAME"
+	ID      lex.TokenType = "ID"
+	COLON   lex.TokenType = "COLON"
+	COMMA   lex.TokenType = "COMMA"
+	COMMENT lex.TokenType = "COMMENT"
+	SEMIC   lex.TokenType = "SEMIC"
+	COLOR   lex.TokenType = "COLOR"
+	VALUE   lex.TokenType = "VALUE"
 )
 
-type TokenType string
-type Token struct {
-	Type    TokenType
-	Literal string
+// Vocabulary of a cube game line, any word other than "Game" is a colour
+var cubeGameSyntax = lex.Config{
+	Punctuation: map[string]lex.TokenType{
+		":": COLON,
+		";": SEMIC,
+		",": COMMA,
+		"#": COMMENT,
+	},
+	Keywords: map[string]lex.TokenType{
+		"Game": GAME,
+	},
+	Ident: COLOR,
+	Int:   VALUE,
+	EOF:   EOL,
 }
 
-var colors = map[string]TokenType{
-	"red":   RED,
-	"green": GREEN,
-	"blue":  BLUE,
+// Count of cubes keyed by colour name
+type CubeSet map[string]int
+
+func (c CubeSet) String() string {
+	parts := []string{}
+	for _, colour := range c.colours() {
+		parts = append(parts, fmt.Sprintf("%d %s", c[colour], colour))
+	}
+	return strings.Join(parts, ", ")
 }
 
-func LookupIdent(ident string) TokenType {
-	if tok, ok := colors[ident]; ok {
-		return tok
+func (c CubeSet) Update(other CubeSet) {
+	for colour, count := range other {
+		if count > c[colour] {
+			c[colour] = count
+		}
 	}
-	return GAME
 }
 
-func newToken(tokenType TokenType, ch byte) *Token {
-	return &Token{Type: tokenType, Literal: string(ch)}
+func (c CubeSet) colours() []string {
+	colours := []string{}
+	for colour := range c {
+		colours = append(colours, colour)
+	}
+	sort.Strings(colours)
+	return colours
 }
 
 type Rules struct {
-	Red   int
-	Green int
-	Blue  int
+	Limits CubeSet
+	// Reject colours missing from Limits instead of treating them as absent
+	Strict bool
 }
 
-func (r *Rules) Update(other Rules) {
-	if other.Red > r.Red {
-		r.Red = other.Red
+// Builds the rules from -cube-config if given, otherwise -cube-limits
+func loadCubeRules() (*Rules, error) {
+	limits := *cubeLimits
+	if *cubeConfig != "" {
+		data, err := os.ReadFile(*cubeConfig)
+		if err != nil {
+			return nil, err
+		}
+		limits = string(data)
 	}
-	if other.Blue > r.Blue {
-		r.Blue = other.Blue
+
+	set, err := parseCubeLimits(limits)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("Loaded cube limits", "limits", set, "strict", *cubeStrict)
+
+	return &Rules{Limits: set, Strict: *cubeStrict}, nil
+}
+
+// Parses a cube limits list or config file, '#' starts a comment running to
+// the end of its line
+func parseCubeLimits(text string) (CubeSet, error) {
+	set, err := newParser(stripComments(text)).parseLimits()
+	if err != nil {
+		return nil, err
 	}
-	if other.Green > r.Green {
-		r.Green = other.Green
+	if len(set) == 0 {
+		return nil, fmt.Errorf("no cube limits found")
 	}
+	return set, nil
 }
 
-func (r *Rules) Compare(other Rules) bool {
-	if other.Red > r.Red {
-		return false
+// Drops everything from a '#' to the end of its line. The lexer only sees a
+// '#' token, so a comment would otherwise end the whole input
+func stripComments(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i], _, _ = strings.Cut(line, "#")
 	}
-	if other.Blue > r.Blue {
-		return false
+	return strings.Join(lines, "\n")
+}
+
+// Reports whether the bag could have produced the set
+func (r *Rules) Compare(other CubeSet) (bool, error) {
+	valid := true
+
+	for _, colour := range other.colours() {
+		limit, ok := r.Limits[colour]
+		if !ok && r.Strict {
+			return false, fmt.Errorf("unknown colour %q", colour)
+		}
+		if other[colour] > limit {
+			valid = false
+		}
+	}
+
+	return valid, nil
+}
+
+// Product of the cubes of every colour, colours in the limits but missing
+// from the set count as zero
+func (r *Rules) Power(set CubeSet) int {
+	power := 1
+
+	for colour := range r.Limits {
+		power *= set[colour]
 	}
-	if other.Green > r.Green {
-		return false
+	for colour, count := range set {
+		if _, ok := r.Limits[colour]; !ok {
+			power *= count
+		}
 	}
-	return true
+
+	return power
 }
 
 type Game struct {
-	Id     int
-	Valid  bool
-	parser *Parser
+	Id      int
+	Valid   bool
+	Sets    []CubeSet // cubes revealed in each round
+	Minimum CubeSet   // fewest cubes of each colour that make the game possible
+	parser  *Parser
 }
 
 func newGame(id int, gameData string) *Game {
@@ -114,209 +227,143 @@ func newGame(id int, gameData string) *Game {
 	return game
 }
 
-func (g *Game) solveGame(rules Rules) {
-	outcome := g.parser.Parse()
-	g.Valid = rules.Compare(outcome)
+func (g *Game) solveGame(rules *Rules) error {
+	sets, err := g.parser.parseGame()
+	if err != nil {
+		return err
+	}
+	g.Sets = sets
+	slog.Debug("Sets of current game", "sets", g.Sets)
+
+	g.Minimum = CubeSet{}
+	for _, set := range g.Sets {
+		g.Minimum.Update(set)
+	}
+
+	g.Valid, err = rules.Compare(g.Minimum)
+	return err
 }
 
 // line based Game parser
 type Parser struct {
-	lexer *Lexer
-	curr  *Token
-	peek  *Token
+	tokens *lex.Stream
 }
 
 func newParser(input string) *Parser {
 	p := &Parser{}
-	p.lexer = newLexer(input)
-	p.nextToken()
-	p.nextToken()
+	p.tokens = lex.NewStream(lex.New(input, cubeGameSyntax))
+	slog.Debug("Current token", "token", p.tokens.Curr())
 
 	return p
 }
 
 func (p *Parser) nextToken() {
-	p.curr = p.peek
-	p.peek = p.lexer.NextToken()
-	if p.curr != nil {
-		slog.Debug("Current token", "token", p.curr)
-	}
+	tok := p.tokens.Next()
+	slog.Debug("Current token", "token", tok)
 }
 
-func (p *Parser) Parse() Rules {
-	rules := Rules{}
-
-	sets := p.parseSets()
-	slog.Debug("Sets of current game", "sets", sets)
-	for _, set := range(sets) {
-		rules.Update(set)
+func (p *Parser) expect(token lex.TokenType) (lex.Token, error) {
+	tok, err := p.tokens.Expect(token)
+	if err == nil {
+		slog.Debug("Current token", "token", p.tokens.Curr())
 	}
-
-	return rules
+	return tok, err
 }
 
-func (p *Parser) parseSets() []Rules {
-	results := []Rules{}
+func (p *Parser) Parse() (CubeSet, error) {
+	minimum := CubeSet{}
 
-	for !p.currTokenIs(EOL) {
-		set := p.parseSet()
-		if set != nil {
-			results = append(results, *set)
-		}
+	sets, err := p.parseGame()
+	if err != nil {
+		return nil, err
 	}
-
-	return results
-}
-
-func (p *Parser) parseSet() *Rules {
-	switch p.curr.Type {
-	case GAME:
-		return p.parseHeader()
-	case COLON:
-		return p.parseResult()
-	case SEMIC:
-		return p.parseResult()
-	default:
-		p.nextToken()
-		return nil
-	}
-}
-
-func (p *Parser) currTokenIs(token TokenType) bool {
-	return p.curr.Type == token
-}
-
-func (p *Parser) peekTokenIs(token TokenType) bool {
-	return p.peek.Type == token
-}
-
-func (p *Parser) expectPeek(token TokenType) bool {
-	if p.peekTokenIs(token) {
-		p.nextToken()
-		return true
-	} else {
-		return false
+	for _, set := range sets {
+		minimum.Update(set)
 	}
-}
 
-func (p *Parser) parseHeader() *Rules {
-	p.nextToken()
-	return nil
+	return minimum, nil
 }
 
-func (p *Parser) parseResult() *Rules {
-	rules := &Rules{}
-
-	if !p.expectPeek(VALUE) {
-		return nil
+// Parses "Game N: <set>; <set>; ..." up to the end of line or a comment
+func (p *Parser) parseGame() ([]CubeSet, error) {
+	if err := p.parseHeader(); err != nil {
+		return nil, err
 	}
 
-	for !p.currTokenIs(SEMIC) {
-
-		if p.currTokenIs(EOL) {
-			return rules
-		}
-
-		num, err := strconv.Atoi(p.curr.Literal)
+	results := []CubeSet{}
+	for {
+		set, err := p.parseSet()
 		if err != nil {
-			return nil
+			return nil, err
 		}
-		p.nextToken()
+		results = append(results, set)
 
-		switch p.curr.Type {
-		case RED:
-			rules.Red = num
-		case BLUE:
-			rules.Blue = num
-		case GREEN:
-			rules.Green = num
+		if p.atEnd() {
+			return results, nil
 		}
-		p.nextToken()
+		if _, err := p.expect(SEMIC); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Parses limits such as "12 red, 13 green, 14 blue", one or many per line
+func (p *Parser) parseLimits() (CubeSet, error) {
+	limits := CubeSet{}
 
-		if p.currTokenIs(COMMA) {
+	for !p.atEnd() {
+		if p.tokens.CurrIs(COMMA, SEMIC) {
 			p.nextToken()
+			continue
 		}
+		set, err := p.parseSet()
+		if err != nil {
+			return nil, err
+		}
+		limits.Update(set)
 	}
 
-	return rules
+	return limits, nil
 }
 
-// Line based Lexer that codifies game information
-type Lexer struct {
-	input    string
-	position int
-	readPos  int
-	ch       byte
+func (p *Parser) atEnd() bool {
+	return p.tokens.CurrIs(EOL, COMMENT)
 }
 
-func newLexer(input string) *Lexer {
-	l := &Lexer{input: input}
-	l.readChar()
-	return l
-}
-
-func (l *Lexer) readChar() {
-	if l.readPos >= len(l.input) {
-		l.ch = 0
-	} else {
-		l.ch = l.input[l.readPos]
+func (p *Parser) parseHeader() error {
+	if _, err := p.expect(GAME); err != nil {
+		return err
 	}
-	l.position = l.readPos
-	l.readPos += 1
-}
-
-func (l *Lexer) NextToken() *Token {
-	tok := &Token{}
-
-	l.skipWhitespace()
-
-	switch l.ch {
-	case ':':
-		tok = newToken(COLON, l.ch)
-	case ';':
-		tok = newToken(SEMIC, l.ch)
-	case ',':
-		tok = newToken(COMMA, l.ch)
-	case '#':
-		tok = newToken(COMMENT, l.ch)
-	case 0:
-		tok.Type = EOL
-		tok.Literal = ""
-	default:
-		if utils.IsLetter(l.ch) {  // Only returns Game or Colors
-			tok.Literal = l.readIdentifier()
-			tok.Type = LookupIdent(tok.Literal)
-			return tok
-		} else if utils.IsDigit(l.ch) {
-			tok.Type = VALUE
-			tok.Literal = l.readNumber()
-			return tok
-		} else {
-			tok = newToken(ILLEGAL, l.ch)
-		}
+	if _, err := p.expect(VALUE); err != nil {
+		return err
 	}
-	l.readChar()
-	return tok
+	_, err := p.expect(COLON)
+	return err
 }
 
-func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' {
-		l.readChar()
-	}
-}
+// Parses "<n> <colour>, <n> <colour>, ..."
+func (p *Parser) parseSet() (CubeSet, error) {
+	set := CubeSet{}
 
-func (l *Lexer) readIdentifier() string {
-	position := l.position
-	for utils.IsLetter(l.ch) {
-		l.readChar()
-	}
-	return l.input[position:l.position]
-}
+	for {
+		value, err := p.expect(VALUE)
+		if err != nil {
+			return nil, err
+		}
+		num, err := strconv.Atoi(value.Literal)
+		if err != nil {
+			return nil, err
+		}
 
-func (l *Lexer) readNumber() string {
-	position := l.position
-	for utils.IsDigit(l.ch) {
-		l.readChar()
+		colour, err := p.expect(COLOR)
+		if err != nil {
+			return nil, err
+		}
+		set[colour.Literal] += num
+
+		if !p.tokens.CurrIs(COMMA) {
+			return set, nil
+		}
+		p.nextToken()
 	}
-	return l.input[position:l.position]
 }
@@ -0,0 +1,33 @@
+package solutions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCubeLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want CubeSet
+	}{
+		{"flag", "12 red, 13 green, 14 blue", CubeSet{"red": 12, "green": 13, "blue": 14}},
+		{"leading comment", "# bag one\n12 red\n13 green\n", CubeSet{"red": 12, "green": 13}},
+		{"comment in between", "12 red # the usual\n13 green\n# more\n14 blue\n", CubeSet{"red": 12, "green": 13, "blue": 14}},
+		{"only comments", "# nothing\n# here\n", nil},
+		{"empty", "", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := parseCubeLimits(tt.text)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("%v: parseCubeLimits() = %v, want an error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: parseCubeLimits() = %v %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
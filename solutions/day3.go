@@ -1,20 +1,25 @@
 package solutions
 
 import (
-	"bufio"
 	"fmt"
-	"log/slog"
 	"io"
-	"os"
+	"log"
+	"log/slog"
+	"regexp"
+	"sort"
 
 	"github.com/ryanpdenoux/advent-of-code/utils"
 )
 
-func Day3(file *os.File) {
+// Grid of digits, dots and symbols
+var Day3Signature = Signature{
+	Line: regexp.MustCompile(`^[^\sA-Za-z]+$`),
+}
+
+func Day3(file io.Reader) []Answer {
 	var (
-		parts   int = 0
-		gears   int = 0
-		gearAcc int = 1
+		parts int = 0
+		gears int = 0
 	)
 
 	schematic := newSchematicFromFile(file)
@@ -23,159 +28,168 @@ func Day3(file *os.File) {
 		parts += number
 	}
 
-	fmt.Printf("The sum of the engine parts is %d\n", parts)
-
-	for _, v := range schematic.gears {
-		if len(v) <= 1 {
-			continue
-		}
-
-		for _, e := range v {
-			gearAcc *= e
-		}
-		gears += gearAcc
-		gearAcc = 1
+	for _, ratio := range schematic.GearRatios() {
+		gears += ratio
 	}
 
-	fmt.Printf("The sum of gear ratios is %d\n", gears)
+	return []Answer{
+		newAnswer(1, "The sum of the engine parts", parts),
+		newAnswer(2, "The sum of gear ratios", gears),
+	}
 }
 
 type EngineSchematic struct {
-	reader *bufio.Reader
-	row    int
-	gears  map[Part][]int
-	prev   []byte
-	curr   []byte
-	next   []byte
+	grid    utils.Grid
+	numbers []SchematicNumber
 }
 
-type Part struct {
-	point Point
-	gear bool
+// Number written in the schematic along with every symbol touching it
+type SchematicNumber struct {
+	Value   int
+	Row     int
+	Col     int
+	Width   int
+	Symbols []Point
 }
 
+// Single touch between a number and a symbol
+type Adjacency struct {
+	Number SchematicNumber
+	Symbol Point
+}
 
 type Point struct {
 	char byte
-	x int
-	y int
+	x    int
+	y    int
 }
 
 func (p Point) String() string {
 	return fmt.Sprintf("char: %v (%3d, %3d)", string(p.char), p.x, p.y)
 }
 
-// Creates an instance from a file handle and sets up the current and next lines
-func newSchematicFromFile(file *os.File) *EngineSchematic {
-	s := &EngineSchematic{}
-	s.reader = bufio.NewReader(file)
-	s.row = 1
-	s.gears = make(map[Part][]int)
-	s.advanceLines()
+// Reads the whole schematic into memory and locates every number
+func newSchematicFromFile(file io.Reader) *EngineSchematic {
+	grid, err := utils.ReadGrid(file)
+	if err != nil {
+		log.Fatalf("Could not read schematic: %v", err)
+	}
+
+	s := &EngineSchematic{grid: grid}
+	s.numbers = s.findNumbers()
 
 	return s
 }
 
-func (s *EngineSchematic) advanceLines() bool {
-	s.row++
-	curr := s.curr
-	next := s.next
-	s.curr = next
-	s.prev = curr
-
-	next, err := s.reader.ReadBytes('\n')
-	if err == io.EOF {
-		s.next = []byte{}
-		return true
-	}
+func (s *EngineSchematic) findNumbers() []SchematicNumber {
+	numbers := []SchematicNumber{}
 
-	s.next = next[:len(next)-1]
-	return true
-}
+	for row := range s.grid {
+		for col := 0; col < len(s.grid[row]); col++ {
+			value, ok := utils.FindNumberInBytes(s.grid[row], col)
+			if !ok {
+				continue
+			}
 
-func (s *EngineSchematic) findPartNumbers() []int {
-	partNumbers := []int{}
+			width := 0
+			for col+width < len(s.grid[row]) && utils.IsDigit(s.grid[row][col+width]) {
+				width++
+			}
+
+			number := SchematicNumber{Value: value, Row: row, Col: col, Width: width}
+			number.Symbols = s.findSymbols(number)
+			numbers = append(numbers, number)
 
-	// Here we must check if there is a current line
-	for s.advanceLines() && len(s.curr) > 0 {
-		rowNumbers := s.findRowNumbers()
-		partNumbers = append(partNumbers, rowNumbers...)
+			// advance index by length of digits
+			col += width - 1
+		}
 	}
 
-	return partNumbers
+	slog.Debug("Found these numbers", "numbers", numbers)
+	return numbers
 }
 
-func (s *EngineSchematic) findRowNumbers() []int {
-	numbers := []int{}
-
-	for i := 0; i < len(s.curr); i++ {
-		number, ok := utils.FindNumberInBytes(s.curr, i)
-		if ok {
-			part, ok := s.checkNumber(number, i)
-			if ok {
-				numbers = append(numbers, number)
-				if part.gear {
-					s.gears[part] = append(s.gears[part], number)
-				}
+// Collects every distinct symbol around a number
+func (s *EngineSchematic) findSymbols(number SchematicNumber) []Point {
+	symbols := []Point{}
+	seen := map[utils.Cell]bool{}
+
+	for col := number.Col; col < number.Col+number.Width; col++ {
+		for _, cell := range s.grid.Neighbours8(utils.Cell{Row: number.Row, Col: col}) {
+			char, _ := s.grid.At(cell)
+			if seen[cell] || !isSymbol(char) {
+				continue
 			}
-			// advance index by length of digits
-			i += utils.LengthOfInt(number) - 1
+			seen[cell] = true
+			symbols = append(symbols, Point{char: char, x: cell.Row, y: cell.Col})
 		}
 	}
 
-	slog.Debug("Found these part numbers", "partNumbers", numbers)
-	return numbers
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].x != symbols[j].x {
+			return symbols[i].x < symbols[j].x
+		}
+		return symbols[i].y < symbols[j].y
+	})
+	return symbols
 }
 
-// Check area surrounding number
-func (s *EngineSchematic) checkNumber(number, pos int) (Part, bool) {
-	numberWidth := utils.LengthOfInt(number)
-	points := s.getPointsToCheck(pos, numberWidth)
-	slog.Debug("Checking points for symbols", "points", points)
+func isSymbol(char byte) bool {
+	return !utils.IsDigit(char) && char != '.'
+}
 
-	return checkPoints(points)
+// Every (number, symbol) pair that touch, a number next to two symbols
+// appears twice
+func (s *EngineSchematic) Adjacencies() []Adjacency {
+	adjacencies := []Adjacency{}
+
+	for _, number := range s.numbers {
+		for _, symbol := range number.Symbols {
+			adjacencies = append(adjacencies, Adjacency{number, symbol})
+		}
+	}
+
+	return adjacencies
 }
 
-func (s *EngineSchematic) getPointsToCheck(pos, width int) []Point {
-	points := []Point{}
+// Numbers touching at least one symbol, each counted once
+func (s *EngineSchematic) findPartNumbers() []int {
+	partNumbers := []int{}
 
-	for _, i := range utils.MakeRange(pos-1, pos+width+1) {
-		if i >= 0 && i < len(s.curr) {
-			point := Point{}
-			point.y = i
+	for _, number := range s.numbers {
+		if len(number.Symbols) > 0 {
+			partNumbers = append(partNumbers, number.Value)
+		}
+	}
 
-			// first line if false
-			if len(s.prev) > 0 {
-				point.char = s.prev[i]
-				point.x = s.row - 1
-				points = append(points, point)
-			}
+	slog.Debug("Found these part numbers", "partNumbers", partNumbers)
+	return partNumbers
+}
 
-			point.char = s.curr[i]
-			point.x = s.row
-			points = append(points, point)
+// Groups the numbers touching each symbol written as char
+func (s *EngineSchematic) SymbolNumbers(char byte) map[Point][]int {
+	symbols := make(map[Point][]int)
 
-			// last line if false
-			if len(s.next) > 0 {
-				point.char = s.next[i]
-				point.x = s.row + 1
-				points = append(points, point)
-			}
+	for _, adjacency := range s.Adjacencies() {
+		if adjacency.Symbol.char == char {
+			symbols[adjacency.Symbol] = append(symbols[adjacency.Symbol], adjacency.Number.Value)
 		}
 	}
 
-	return points
+	return symbols
 }
 
-func checkPoints(points []Point) (Part, bool) {
-	for _, point := range points {
-		if !utils.IsDigit(point.char) && point.char != '.' {
-			part := Part{point: point}
-			if point.char == '*' {
-				part.gear = true
-			}
-			return part, true
+// Products of the two numbers around every '*' touching exactly two numbers
+func (s *EngineSchematic) GearRatios() []int {
+	ratios := []int{}
+
+	for gear, numbers := range s.SymbolNumbers('*') {
+		if len(numbers) != 2 {
+			continue
 		}
+		slog.Debug("Found gear", "gear", gear, "numbers", numbers)
+		ratios = append(ratios, numbers[0]*numbers[1])
 	}
-	return Part{}, false
+
+	return ratios
 }
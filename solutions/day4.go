@@ -2,63 +2,110 @@ package solutions
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
-	"math"
-	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
-	"github.com/ryanpdenoux/advent-of-code/utils"
+	"github.com/ryanpdenoux/advent-of-code/utils/scoring"
 )
 
-func Day4(file *os.File) {
-	var points int
-	var count int
+var (
+	scratchScoring = flag.String(
+		"scratch-scoring",
+		"",
+		"score Day4 with a single strategy (doubling, linear, copies) instead of both parts",
+	)
+	scratchReport = flag.Bool(
+		"scratch-report",
+		false,
+		"print how many copies of each Day4 card are won",
+	)
+)
 
-	cards := make(CopyMap)
-	scanner := bufio.NewScanner(file)
-	scoring := &ValueScoring{scoringBase: 2}
-	parser := newGameParser(":", "|")
-	for i := 1; scanner.Scan(); i++ {
-		cards[i] += 1
-		line := scanner.Text()
-		game := parser.ParseGame(line)
-		score := game.scoreGame(scoring)
-		cards.insertCopies(game, i)
-		points += score
-		count = i
-	}
-	fmt.Printf("Value of Scratchcards: %v\n", points)
-	fmt.Printf("Count of all cards: %v\t%v\n", cards.sumValues(count), cards)
+// Strategies by name, -scratch-scoring picks one
+var scratchScorings = map[string]scoring.ListScorable{
+	"doubling": scoring.Each(scoring.Doubling{Base: 2}),
+	"linear":   scoring.Each(scoring.Linear{PointsPerMatch: 1}),
+	"copies":   scoring.Copies{},
+}
+
+var scratchLabels = map[string]string{
+	"doubling": "Value of Scratchcards",
+	"linear":   "Matches on Scratchcards",
+	"copies":   "Count of all cards",
 }
 
-type Scorable interface {
-	ScoreGame(Set) int
+// Puzzle part each strategy answers, linear is a variant of part 1
+var scratchParts = map[string]int{
+	"doubling": 1,
+	"linear":   1,
+	"copies":   2,
 }
 
-type CopyMap map[int]int
+// "Card N: 41 48 83 | 83 86 6"
+var Day4Signature = Signature{
+	Prefix: "Card ",
+	Line:   regexp.MustCompile(`^Card +\d+:[\d ]+\|[\d ]+$`),
+}
 
-func (m CopyMap) insertCopies(game *ScratchGame, offset int) {
-	numMatches :=  len(game.matches)
+func Day4(file io.Reader) []Answer {
+	names := []string{"doubling", "copies"}
+	if *scratchScoring != "" {
+		names = []string{*scratchScoring}
+	}
 
-	for _, i := range utils.MakeRange(1, numMatches+1) {
-		pos := offset+i
-		scaler := m[offset]
-		m[pos] += scaler
+	answers := []Answer{}
+	matches := []int{}
+	scanner := bufio.NewScanner(file)
+	parser := newGameParser(":", "|")
+	for scanner.Scan() {
+		line := scanner.Text()
+		game := parser.ParseGame(line)
+		matches = append(matches, len(game.matches))
 	}
-	slog.Debug("Updated map", "map", m)
-}
 
-func (m CopyMap) sumValues(end int) int {
-	var sum int
+	for _, name := range names {
+		strategy, ok := scratchScorings[name]
+		if !ok {
+			log.Fatalf("Unknown scoring strategy %q", name)
+		}
+		scores, err := strategy.ScoreAll(matches)
+		if err != nil {
+			log.Fatalf("Could not score cards with %v: %v", name, err)
+		}
+		slog.Debug("Scored cards", "strategy", name, "scores", scores)
 
-	for i := 1; i <= end; i++ {
-		sum += m[i]
+		total, err := scoring.Sum(scores)
+		if err != nil {
+			log.Fatalf("Could not score cards with %v: %v", name, err)
+		}
+		answer := newAnswer(scratchParts[name], scratchLabels[name], total)
+
+		if name == "copies" && *scratchReport {
+			table, err := scoring.NewCopyTable(matches)
+			if err != nil {
+				log.Fatalf("Could not build the copy report: %v", err)
+			}
+			answer.Report = copyReport(table)
+		}
+		answers = append(answers, answer)
 	}
 
-	return sum
+	return answers
+}
+
+// Copies of each card held at the end, one line per card
+func copyReport(table *scoring.CopyTable) []string {
+	lines := []string{}
+	for i, count := range table.Copies() {
+		lines = append(lines, fmt.Sprintf("Card %d: %d copies", i+1, count))
+	}
+	return lines
 }
 
 type Set map[int]bool
@@ -170,23 +217,3 @@ type ScratchGame struct {
 	playerNums  Set
 	matches     Set
 }
-
-func (s *ScratchGame) scoreGame(scoringSystem *ValueScoring) int {
-	if len(s.matches) == 0 {
-		return 0
-	}
-
-	score := scoringSystem.ScoreGame(s.matches)
-	slog.Debug("Calculated score for game","matches", s.matches, "score", score, "game", s)
-	return score
-}
-
-type ValueScoring struct {
-	scoringBase int
-}
-
-func (s *ValueScoring) ScoreGame(matches Set) int {
-	numMatches := len(matches)
-	score := int(math.Pow(float64(s.scoringBase), float64(numMatches - 1)))
-	return score
-}
@@ -1,17 +1,22 @@
 package solutions
 
 import (
-	"bufio"
-	"fmt"
+	"io"
 	"log"
 	"log/slog"
-	"os"
+	"regexp"
 	"strings"
 
 	"github.com/ryanpdenoux/advent-of-code/utils"
 )
 
-func Day5(file *os.File) {
+// Seed list followed by "x-to-y map:" sections
+var Day5Signature = Signature{
+	Prefix: "seeds:",
+	Line:   regexp.MustCompile(`^(seeds:[\d ]+|[a-z]+-to-[a-z]+ map:|\d+ \d+ \d+)$`),
+}
+
+func Day5(file io.Reader) []Answer {
 	min := 1024 * 1024 * 1024 * 1024
 
 	parser := createAlmanacParser(file)
@@ -23,10 +28,12 @@ func Day5(file *os.File) {
 		}
 	}
 
-	fmt.Printf("Plant here: %v\n", min)
-
 	location := almanac.FindLocationFromRange()
-	fmt.Printf("Next here: %v\n", location)
+
+	return []Answer{
+		newAnswer(1, "Plant here", min),
+		newAnswer(2, "Next here", location),
+	}
 }
 
 type Almanac struct {
@@ -180,12 +187,13 @@ type SeedRange struct {
 
 // Parsing Logic
 type AlmanacParser struct {
-	scanner bufio.Scanner
+	sections *utils.SectionScanner
 }
 
-func createAlmanacParser(file *os.File) *AlmanacParser {
+func createAlmanacParser(file io.Reader) *AlmanacParser {
 	p := &AlmanacParser{}
-	p.scanner = *bufio.NewScanner(file)
+	p.sections = utils.NewSectionScanner(file)
+	p.sections.Trim = true
 	return p
 }
 
@@ -202,37 +210,37 @@ func (p *AlmanacParser) createAlamanac() *Almanac {
 	return a
 }
 
-func (p *AlmanacParser) getNextTokens() []string {
-	tokens := []string{}
-
-	for p.scanner.Scan() {
-		line := p.scanner.Text()
-		if len(line) == 0 {
-			break
-		}
-		line = strings.TrimSuffix(line, "\n")
-		tokens = append(tokens, line)
+func (p *AlmanacParser) getNextSection() utils.Section {
+	if !p.sections.Scan() {
+		log.Fatalf("Almanac ended before all sections were read: %v\n", p.sections.Err())
 	}
-
-	return tokens
+	return p.sections.Section()
 }
 
 func (p *AlmanacParser) getSeeds() []int {
-	seedLine := p.getNextTokens()
-	if len(seedLine) != 1 {
-		log.Fatalf("Invalid Header %v\n", seedLine)
+	section := p.getNextSection()
+	if len(section.Lines) != 1 {
+		log.Fatalf("Invalid Header at line %d: %v\n", section.Start, section.Lines)
 	}
-	seedLine = strings.Split(seedLine[0], ":")
+	seedLine := strings.Split(section.Lines[0], ":")
 	strSeeds := strings.Fields(seedLine[1])
 	seeds := utils.StringSliceToIntSlice(strSeeds)
 	return seeds
 }
 
 func (p *AlmanacParser) getNextMapping() AlmanacMapper {
-	strMapping := p.getNextTokens()
+	section := p.getNextSection()
+	if section.Header == "" {
+		log.Fatalf("Mapping without a header at line %d\n", section.Start)
+	}
+	slog.Debug("Parsing mapping", "name", section.Title())
+
 	cMappings := []MappingInstruction{}
-	for _, aMapping := range strMapping[1:] {
+	for i, aMapping := range section.Lines {
 		aFields := strings.Fields(aMapping)
+		if len(aFields) != 3 {
+			log.Fatalf("Invalid mapping at line %d: %v\n", section.LineNumber(i), aMapping)
+		}
 		ints := utils.StringSliceToIntSlice(aFields)
 		c := MappingInstruction{ints[0], ints[1], ints[2]}
 		cMappings = append(cMappings, c)
@@ -2,30 +2,53 @@ package solutions
 
 import (
 	"bufio"
-	"fmt"
+	"flag"
 	"io"
 	"log"
 	"log/slog"
-	"os"
+	"math/big"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/ryanpdenoux/advent-of-code/utils"
 )
 
-func Day6(file *os.File) {
+var regattaBruteForce = flag.Bool(
+	"regatta-brute-force",
+	false,
+	"count Day6 winning charge times by trying every one of them",
+)
+
+// "Time:" and "Distance:" rows
+var Day6Signature = Signature{
+	Prefix: "Time:",
+	Line:   regexp.MustCompile(`^(Time|Distance):[\d ]+$`),
+}
+
+func Day6(file io.Reader) []Answer {
 	var accumulatedRecord int = 1
 
 	parser := createRegattaParser(file)
 	records := parser.Parse()
 	boat := &RegattaBoat{1}
 
+	race := boat.SolveRace
+	if *regattaBruteForce {
+		race = boat.AttemptRace
+	}
+
 	for _, record := range records {
-		numBetterRecords := boat.AttemptRace(record)
+		numBetterRecords := race(record)
 		accumulatedRecord = accumulatedRecord * numBetterRecords
 	}
 
-	fmt.Printf("Accumulated Records Beaten: %v\n", accumulatedRecord)
+	record := parser.AlternateParse()
+
+	return []Answer{
+		newAnswer(1, "Accumulated Records Beaten", accumulatedRecord),
+		newAnswer(2, "Ways to beat the single long race", race(record)),
+	}
 }
 
 type RegattaBoat struct {
@@ -54,10 +77,58 @@ func (b *RegattaBoat) AttemptRace(record RegattaRecord) int {
 		}
 	}
 
-	slog.Info("Boat was able to beat record n times", "n", right-left)
+	if right == 0 {
+		return 0
+	}
+
+	slog.Info("Boat was able to beat record n times", "n", right-left+1)
 	return right-left+1
 }
 
+// Returns number of ways in which a race can be won in constant time.
+// Holding for h wins when v*h*(T-h) > D, so the winning charge times lie
+// strictly between the roots of v*h^2 - v*T*h + D. The roots are estimated
+// with an integer square root then nudged onto the exact boundary. The
+// discriminant outgrows an int for long races, so it is taken with big ints
+func (b *RegattaBoat) SolveRace(record RegattaRecord) int {
+	v, t, d := b.baseVelocity, record.Time, record.Distance
+	// v*(t-h)*h > d, divided through by v*h so the product never overflows
+	wins := func(h int) bool {
+		if h <= 0 {
+			return false
+		}
+		return t-h > d/b.velocity(h)
+	}
+
+	if !wins(t / 2) {
+		return 0
+	}
+
+	vt := new(big.Int).Mul(big.NewInt(int64(v)), big.NewInt(int64(t)))
+	disc := new(big.Int).Mul(vt, vt)
+	disc.Sub(disc, new(big.Int).Mul(big.NewInt(int64(4*v)), big.NewInt(int64(d))))
+	low := 0
+	if disc.Sign() > 0 {
+		root := new(big.Int).Sub(vt, disc.Sqrt(disc))
+		low = int(root.Quo(root, big.NewInt(int64(2*v))).Int64())
+	}
+	if low < 0 {
+		low = 0
+	}
+	for low > 0 && wins(low-1) {
+		low--
+	}
+	for !wins(low) {
+		low++
+	}
+
+	// distance is symmetric around t/2
+	high := t - low
+
+	slog.Info("Boat was able to beat record n times", "n", high-low+1)
+	return high - low + 1
+}
+
 func (b *RegattaBoat) velocity(chargeTime int) int {
 	return b.baseVelocity * chargeTime
 }
@@ -82,8 +153,9 @@ func createRegattaParser(file io.Reader) *RegattaParser {
 func (p *RegattaParser) Parse() []RegattaRecord {
 	records := []RegattaRecord{}
 
-	strTimes := p.prepareLine()
-	strDists := p.prepareLine()
+	p.readLines()
+	strTimes := p.prepareLine(p.rawTime)
+	strDists := p.prepareLine(p.rawDist)
 	times := utils.StringSliceToIntSlice(strTimes)
 	dists := utils.StringSliceToIntSlice(strDists)
 	slog.Debug("Found times and dists", "times", times, "dists", dists)
@@ -95,10 +167,12 @@ func (p *RegattaParser) Parse() []RegattaRecord {
 	return records
 }
 
+// Reads the input ignoring the spaces between numbers (part 2 kerning)
 func (p *RegattaParser) AlternateParse() RegattaRecord {
 	record := RegattaRecord{}
 
-	strTimes := p.prepareLine()
+	p.readLines()
+	strTimes := p.prepareLine(p.rawTime)
 	strTime := strings.Join(strTimes, "")
 	slog.Debug("Raw time", "Time", strTime)
 	time, err := strconv.Atoi(strTime)
@@ -108,7 +182,7 @@ func (p *RegattaParser) AlternateParse() RegattaRecord {
 	record.Time = time
 	slog.Debug("Found time", "time", time)
 
-	strDists := p.prepareLine()
+	strDists := p.prepareLine(p.rawDist)
 	strDist := strings.Join(strDists, "")
 	dist, err := strconv.Atoi(strDist)
 	if err != nil {
@@ -119,9 +193,22 @@ func (p *RegattaParser) AlternateParse() RegattaRecord {
 	return record
 }
 
-func (p *RegattaParser) prepareLine() []string {
+// Keeps both lines so the input can be parsed either way
+func (p *RegattaParser) readLines() {
+	if p.rawTime != "" {
+		return
+	}
+
+	p.scanner.Scan()
+	p.rawTime = p.scanner.Text()
 	p.scanner.Scan()
-	rawLine := p.scanner.Text()
+	p.rawDist = p.scanner.Text()
+}
+
+func (p *RegattaParser) prepareLine(rawLine string) []string {
 	line := strings.Split(rawLine, ":")
+	if len(line) != 2 {
+		log.Fatalf("Line not properly formatted: %v\n", rawLine)
+	}
 	return strings.Fields(line[1])
 }
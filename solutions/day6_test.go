@@ -0,0 +1,35 @@
+package solutions
+
+import "testing"
+
+func TestSolveRace(t *testing.T) {
+	for _, velocity := range []int{1, 2, 3} {
+		boat := &RegattaBoat{velocity}
+		for time := 0; time <= 40; time++ {
+			for distance := 0; distance <= 3*time*time/4+1; distance++ {
+				record := RegattaRecord{time, distance}
+				if got, want := boat.SolveRace(record), boat.AttemptRace(record); got != want {
+					t.Errorf("v=%d SolveRace(%+v) = %v, want %v", velocity, record, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSolveRaceHuge(t *testing.T) {
+	tests := []struct {
+		record RegattaRecord
+		want   int
+	}{
+		// v*t*t alone is far beyond an int
+		{RegattaRecord{4_000_000_000, 10}, 3_999_999_999},
+		{RegattaRecord{4_000_000_000, 4_000_000_000_000_000_000}, 0},
+		{RegattaRecord{4_000_000_000, 3_999_999_999_999_999_999}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := (&RegattaBoat{1}).SolveRace(tt.record); got != tt.want {
+			t.Errorf("SolveRace(%+v) = %v, want %v", tt.record, got, tt.want)
+		}
+	}
+}
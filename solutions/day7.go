@@ -2,126 +2,115 @@ package solutions
 
 import (
 	"bufio"
+	"cmp"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
-	"os"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
-
-	"github.com/ryanpdenoux/advent-of-code/utils"
 )
 
-var jokerVariant = flag.Bool(
-	"joker-variant",
-	false,
-	"declare if using joker variation for Day7",
+var (
+	jokerVariant = flag.Bool(
+		"joker-variant",
+		false,
+		"declare if using joker variation for Day7, same as -camel-rules joker",
+	)
+	camelRuleSet = flag.String(
+		"camel-rules",
+		"",
+		"score Day7 with a single rule set (normal, joker) instead of both parts",
+	)
 )
 
-func Day7(file *os.File) {
-	if !*jokerVariant {
-		gameChoice()
-	}
-
-	parser := newCamelGameParser(file, *jokerVariant)
-	game := parser.Parse()
-	fmt.Printf("Game Winnings: %v\n", game.Winnings())
+// "32T3K 765", a hand and its bid
+var Day7Signature = Signature{
+	Line: regexp.MustCompile(`^[2-9TJQKA]{5} \d+$`),
 }
 
-func gameChoice() {
-	var choice string
-	allowed := []string{"normal", "variant"}
+func Day7(file io.Reader) []Answer {
+	answers := []Answer{}
+	parser := newCamelGameParser(file)
 
-	for !utils.Contains(allowed, choice) {
-		fmt.Print("Pick a game type (normal, variant): ")
-		fmt.Scan(&choice)
-		if !utils.Contains(allowed, choice) {
-			fmt.Print("  Please pick normal or variant\r")
+	for _, name := range chosenCamelRules() {
+		rules, ok := camelRuleSets[name]
+		if !ok {
+			log.Fatalf("Unknown Camel Cards rule set %q", name)
+		}
+		game, err := parser.Parse(rules)
+		if err != nil {
+			log.Fatal(err)
 		}
+		answers = append(answers, newAnswer(camelPart(name), fmt.Sprintf("Game Winnings (%s)", name), game.Winnings()))
 	}
-	if choice == "variant" {
-		*jokerVariant = true
+
+	return answers
+}
+
+// Both parts by default, otherwise only the rule set picked by flags
+func chosenCamelRules() []string {
+	if *camelRuleSet != "" {
+		return []string{*camelRuleSet}
+	}
+	if *jokerVariant {
+		return []string{"joker"}
 	}
+	return []string{"normal", "joker"}
 }
 
 type CamelGame struct {
-	length int
-	head   *CamelHand
+	hands  []*CamelHand
+	sorted bool
 }
 
 func (g CamelGame) String() string {
 	var sb strings.Builder
-	curr := g.head
 
-	if curr == nil {
+	if len(g.hands) == 0 {
 		return fmt.Sprint("No cards inserted for game")
 	}
 
-	for curr.Next != nil {
-		sb.WriteString(curr.String())
-		sb.WriteString("->")
-		curr = curr.Next
+	for i, hand := range g.Ranked() {
+		if i > 0 {
+			sb.WriteString("->")
+		}
+		sb.WriteString(hand.String())
 	}
 
-	sb.WriteString(curr.String())
 	return sb.String()
 }
 
 func (g *CamelGame) InsertHand(hand *CamelHand) {
-	g.length += 1
-	curr := g.head
-
-	if curr == nil {
-		slog.Debug("First Node", "node", hand)
-		g.head = hand
-		return
-	}
+	hand.order = len(g.hands)
+	g.hands = append(g.hands, hand)
+	g.sorted = false
+}
 
-	if hand.Less(curr) {
-		slog.Debug("Hand smaller than Head", "hand", hand, "head", curr)
-		hand.Next = curr
-		g.head = hand
-		return
+// Returns the hands from weakest (rank 1) to strongest
+func (g *CamelGame) Ranked() []*CamelHand {
+	if !g.sorted {
+		slices.SortFunc(g.hands, (*CamelHand).Compare)
+		g.sorted = true
+		slog.Debug("Ranked hands", "hands", g.hands)
 	}
 
-	for curr.Next != nil {
-		if hand.Less(curr.Next) {
-			slog.Debug("Inserted Here", "point", fmt.Sprintf("...%v->%v->%v...", curr, hand, curr.Next))
-			hand.Next = curr.Next
-			curr.Next = hand
-			return
-		}
-		curr = curr.Next
-	}
-	slog.Debug("Largest Hand", "game", fmt.Sprintf("...%v->%v", curr, hand))
-	curr.Next = hand
-	return
+	ranked := make([]*CamelHand, len(g.hands))
+	copy(ranked, g.hands)
+	return ranked
 }
 
 func (g *CamelGame) Winnings() int {
 	var sum int
-	var prev *CamelHand
-	curr := g.head
 
-	if curr == nil {
-		return 0
+	for i, hand := range g.Ranked() {
+		sum += hand.bid * (i + 1)
 	}
 
-	i, j := 1, 1
-	for curr.Next != nil {
-		if prev != nil && curr.cards == prev.cards {
-			sum += curr.bid * j
-		} else {
-			sum += curr.bid * i
-			j = i
-		}
-		i++
-		prev = curr
-		curr = curr.Next
-	}
-	sum += curr.bid * g.length
-
 	return sum
 }
 
@@ -140,7 +129,7 @@ type CamelHand struct {
 	cards    [5]CamelCard
 	bid      int
 	strength int
-	Next     *CamelHand
+	order    int // position in the input, breaks ties between equal hands
 }
 
 func (h CamelHand) String() string {
@@ -148,103 +137,193 @@ func (h CamelHand) String() string {
 }
 
 func (h *CamelHand) Less(hand *CamelHand) bool {
-	if h.strength < hand.strength {
-		return true
-	}
-	if h.strength > hand.strength {
-		return false
+	return h.Compare(hand) < 0
+}
+
+// Total order over hands: type, then cards left to right, then bid and
+// finally input order so identical hands still rank deterministically
+func (h *CamelHand) Compare(hand *CamelHand) int {
+	if c := cmp.Compare(h.strength, hand.strength); c != 0 {
+		return c
 	}
 	for i := 0; i < len(h.cards); i++ {
-		ourCard, theirCard := h.cards[i], hand.cards[i]
-		if ourCard.rank < theirCard.rank {
-			return true
-		}
-		if ourCard.rank > theirCard.rank {
-			return false
+		if c := cmp.Compare(h.cards[i].rank, hand.cards[i].rank); c != 0 {
+			return c
 		}
 	}
-	return false
+	if c := cmp.Compare(h.bid, hand.bid); c != 0 {
+		return c
+	}
+	return cmp.Compare(h.order, hand.order)
 }
 
-func newCamelHand(bs []byte, variant bool) *CamelHand {
+func newCamelHand(bs []byte, rules CamelRules) (*CamelHand, error) {
 	hand := &CamelHand{}
 	hand.cards = [5]CamelCard{}
 
+	if len(bs) != len(hand.cards) {
+		return nil, fmt.Errorf("Hand %q does not have %d cards", bs, len(hand.cards))
+	}
+
 	for i, b := range bs {
-		hand.cards[i] = newCamelCard(b, variant)
+		card, err := rules.Card(b)
+		if err != nil {
+			return nil, err
+		}
+		hand.cards[i] = card
 	}
-	matchedCards := hand.matchCards(variant)
-	hand.determineType(matchedCards)
+	hand.strength = rules.Classify(hand.matchCards(rules))
 
-	return hand
+	return hand, nil
 }
 
-// REVIEW following two functions probably fit better on a "Rules" struct
-func (h *CamelHand) determineType(matchedCards []int) {
-	if utils.Contains(matchedCards, 5) {
-		h.strength = FiveOfAKind
-		return
+// Counts how often each card occurs after the rules substitute wildcards
+func (h *CamelHand) matchCards(rules CamelRules) []int {
+	matchedCards := make(map[CamelCard]int)
+	occurences := []int{}
+
+	for i := 0; i < len(h.cards); i++ {
+		matchedCards[h.cards[i]] += 1
 	}
+	matchedCards = rules.Substitute(matchedCards)
 
-	if utils.Contains(matchedCards, 4) {
-		h.strength = FourOfAKind
-		return
+	for _, count := range matchedCards {
+		occurences = append(occurences, count)
 	}
+	sort.Sort(sort.Reverse(sort.IntSlice(occurences)))
+
+	return occurences
+}
 
-	if utils.Contains(matchedCards, 3) && utils.Contains(matchedCards, 2) {
-		h.strength = FullHouse
-		return
+// Decides how cards rank, which cards are wild and what type a hand is
+type CamelRules interface {
+	// Maps a card symbol from the input to a card
+	Card(symbol byte) (CamelCard, error)
+	// Reassigns wildcards in a count of cards to whatever makes the best hand
+	Substitute(counts map[CamelCard]int) map[CamelCard]int
+	// Turns card counts (largest first) into a hand strength, higher wins
+	Classify(counts []int) int
+}
+
+var camelRuleSets = map[string]CamelRules{
+	"normal": NormalRules,
+	"joker":  JokerRules,
+}
+
+// Puzzle part a rule set answers, registered rule sets are part 1 variants
+func camelPart(name string) int {
+	if name == "joker" {
+		return 2
 	}
+	return 1
+}
+
+// Makes a rule set selectable with -camel-rules
+func RegisterCamelRules(name string, rules CamelRules) {
+	camelRuleSets[name] = rules
+}
+
+// Named hand type, Match receives card counts sorted largest first
+type HandCategory struct {
+	Name     string
+	Strength int
+	Match    func(counts []int) bool
+}
 
-	if utils.Contains(matchedCards, 3) {
-		h.strength = ThreeOfAKind
-		return
+func countsStartWith(prefix ...int) func([]int) bool {
+	return func(counts []int) bool {
+		if len(counts) < len(prefix) {
+			return false
+		}
+		for i, count := range prefix {
+			if counts[i] < count {
+				return false
+			}
+		}
+		return true
 	}
+}
+
+// Hand types of the puzzle, strongest first
+var CamelCategories = []HandCategory{
+	{"five of a kind", FiveOfAKind, countsStartWith(5)},
+	{"four of a kind", FourOfAKind, countsStartWith(4)},
+	{"full house", FullHouse, countsStartWith(3, 2)},
+	{"three of a kind", ThreeOfAKind, countsStartWith(3)},
+	{"two pairs", TwoPairs, countsStartWith(2, 2)},
+	{"one pair", OnePair, countsStartWith(2)},
+	{"high card", HighCard, countsStartWith()},
+}
 
-	if utils.Contains(matchedCards, 2) && len(matchedCards) == 3 {
-		h.strength = TwoPairs
-		return
+// Rule set described by data: cards rank in the order of Ranking (weakest
+// first), an optional Wild symbol ranks below every other card and joins
+// the most common card, and hands take the first matching Category.
+type CamelRuleSet struct {
+	Ranking    string
+	Wild       byte
+	Categories []HandCategory
+}
+
+var (
+	NormalRules = &CamelRuleSet{
+		Ranking:    "23456789TJQKA",
+		Categories: CamelCategories,
 	}
+	JokerRules = &CamelRuleSet{
+		Ranking:    "23456789TJQKA",
+		Wild:       'J',
+		Categories: CamelCategories,
+	}
+)
 
-	if utils.Contains(matchedCards, 2) {
-		h.strength = OnePair
-		return
+func (r *CamelRuleSet) Card(symbol byte) (CamelCard, error) {
+	if r.Wild != 0 && symbol == r.Wild {
+		return CamelCard{1, rune(symbol)}, nil
 	}
 
-	h.strength = HighCard
-	return
+	i := strings.IndexByte(r.Ranking, symbol)
+	if i < 0 {
+		return CamelCard{}, fmt.Errorf("Not a valid CamelCard: %q", symbol)
+	}
+	return CamelCard{i + 2, rune(symbol)}, nil
 }
 
-func (h *CamelHand) matchCards(joker bool) []int {
+func (r *CamelRuleSet) Substitute(counts map[CamelCard]int) map[CamelCard]int {
 	var max int
 	var maxCard CamelCard
-	matchedCards := make(map[CamelCard]int)
-	occurences := []int{}
 
-	for i := 0; i < len(h.cards); i++ {
-		matchedCards[h.cards[i]] += 1
+	if r.Wild == 0 {
+		return counts
+	}
+	wild := CamelCard{1, rune(r.Wild)}
+	wilds, ok := counts[wild]
+	if !ok || len(counts) == 1 {
+		return counts
 	}
 
-	// joker variant game
-	if joker {
-		for s, v := range matchedCards {
-			if v > max && s != Joker{
-				max = v
-				maxCard = s
-			}
+	for card, count := range counts {
+		if card == wild {
+			continue
 		}
-		val, ok := matchedCards[Joker]
-		if ok {
-			matchedCards[maxCard] += val
-			delete(matchedCards, Joker)
+		// prefer the higher card on ties so the result is deterministic
+		if count > max || (count == max && card.rank > maxCard.rank) {
+			max = count
+			maxCard = card
 		}
 	}
+	counts[maxCard] += wilds
+	delete(counts, wild)
 
-	for _, count := range matchedCards {
-		occurences = append(occurences, count)
-	}
+	return counts
+}
 
-	return occurences
+func (r *CamelRuleSet) Classify(counts []int) int {
+	for _, category := range r.Categories {
+		if category.Match(counts) {
+			return category.Strength
+		}
+	}
+	return 0
 }
 
 type CamelCard struct {
@@ -256,91 +335,53 @@ func (c CamelCard) String() string {
 	return string(c.symbol)
 }
 
-var (
-	Ace   = CamelCard{14, 'A'}
-	King  = CamelCard{13, 'K'}
-	Queen = CamelCard{12, 'Q'}
-	Jack  = CamelCard{11, 'J'}
-	Ten   = CamelCard{10, 'T'}
-	Nine  = CamelCard{9, '9'}
-	Eight = CamelCard{8, '8'}
-	Seven = CamelCard{7, '7'}
-	Six   = CamelCard{6, '6'}
-	Five  = CamelCard{5, '5'}
-	Four  = CamelCard{4, '4'}
-	Three = CamelCard{3, '3'}
-	Two   = CamelCard{2, '2'}
-	Joker = CamelCard{1, 'J'}
-)
-
-func newCamelCard(b byte, jokerRule bool) CamelCard {
-	switch b {
-	case 'A':
-		return Ace
-	case 'K':
-		return King
-	case 'Q':
-		return Queen
-	case 'J':
-		if jokerRule {
-			return Joker
-		}
-		return Jack
-	case 'T':
-		return Ten
-	case '9':
-		return Nine
-	case '8':
-		return Eight
-	case '7':
-		return Seven
-	case '6':
-		return Six
-	case '5':
-		return Five
-	case '4':
-		return Four
-	case '3':
-		return Three
-	case '2':
-		return Two
-	}
-	log.Fatalf("Not a valid CamelCard: %v\n", b)
-	return CamelCard{}
-}
-
 type CamelGameParser struct {
 	scanner *bufio.Scanner
-	variant bool
+	lines   []string
 }
 
-func newCamelGameParser(file *os.File, variant bool) *CamelGameParser {
+func newCamelGameParser(file io.Reader) *CamelGameParser {
 	p := &CamelGameParser{}
 	p.scanner = bufio.NewScanner(file)
-	p.variant = variant
 	return p
 }
 
-func (p *CamelGameParser) Parse() *CamelGame {
+// Builds a game scored by rules, the input is read once and reused
+func (p *CamelGameParser) Parse(rules CamelRules) (*CamelGame, error) {
 	game := &CamelGame{}
 
-	for p.scanner.Scan() {
-		line := p.scanner.Text()
-		hand := p.parseHand(line)
+	if p.lines == nil {
+		p.lines = []string{}
+		for p.scanner.Scan() {
+			p.lines = append(p.lines, p.scanner.Text())
+		}
+	}
+
+	for i, line := range p.lines {
+		hand, err := p.parseHand(line, rules)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", i+1, err)
+		}
 		game.InsertHand(hand)
 	}
 
 	slog.Debug("Parsed Game", "game", game)
-	return game
+	return game, nil
 }
 
-func (p *CamelGameParser) parseHand(line string) *CamelHand {
+func (p *CamelGameParser) parseHand(line string, rules CamelRules) (*CamelHand, error) {
 	fields := strings.Fields(line)
-	hand := newCamelHand([]byte(fields[0]), p.variant)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("Expected a hand and a bid: %q", line)
+	}
+	hand, err := newCamelHand([]byte(fields[0]), rules)
+	if err != nil {
+		return nil, err
+	}
 	bid, err := strconv.Atoi(fields[1])
 	if err != nil {
-		log.Fatal("No good")
+		return nil, err
 	}
 	hand.bid = bid
-	return hand
+	return hand, nil
 }
@@ -1,38 +1,82 @@
 package solutions
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
-	"os"
+	"regexp"
 	"strings"
+
+	"github.com/ryanpdenoux/advent-of-code/utils"
+	"github.com/ryanpdenoux/advent-of-code/utils/graph"
+	"github.com/ryanpdenoux/advent-of-code/utils/parse"
 )
 
-func Day8(file *os.File) {
+// Instructions then "AAA = (BBB, CCC)" nodes
+var Day8Signature = Signature{
+	Line: regexp.MustCompile(`^([LR]+|\w{3} = \(\w{3}, \w{3}\))$`),
+}
+
+func Day8(file io.Reader) []Answer {
 	var steps int
+	answers := []Answer{}
 
 	parser := newWastelandParser(file)
 	instructions, directions := parser.Parse()
 	desert := newDesert(instructions, directions)
-	steps = desert.TraverseDesert("AAA", "ZZZ")
 
-	fmt.Printf("Number of steps taken: %v\n", steps)
+	// some inputs (e.g. the part 2 example) have no AAA node, and following
+	// instructions that never land on ZZZ would walk forever
+	if desert.Reaches("AAA", "ZZZ") {
+		steps = desert.TraverseDesert("AAA", "ZZZ")
+		answers = append(answers, newAnswer(1, "Number of steps taken", steps))
+	} else {
+		slog.Warn("Instructions never lead from AAA to ZZZ, skipping single traversal")
+	}
+
+	steps, err := desert.TraverseGhosts(isGhostStart, isGhostEnd)
+	if err != nil {
+		log.Fatalf("Ghosts never line up: %v", err)
+	}
+	answers = append(answers, newAnswer(2, "Number of steps taken by ghosts", steps))
+
+	return answers
+}
+
+func isGhostStart(node string) bool {
+	return strings.HasSuffix(node, "A")
+}
+
+func isGhostEnd(node string) bool {
+	return strings.HasSuffix(node, "Z")
 }
 
 type Desert struct {
 	instructions *DirectionRing
-	directions MapInstructions
+	directions   MapInstructions
+	graph        *graph.Graph[string]
 }
 
 func newDesert(i *DirectionRing, d MapInstructions) *Desert {
 	desert := &Desert{
 		instructions: i,
-		directions: d,
+		directions:   d,
+		graph:        d.Graph(),
 	}
 	return desert
 }
 
+// Whether following the instructions from start ever lands on end
+func (d *Desert) Reaches(start, end string) bool {
+	if _, ok := d.directions[start]; !ok {
+		return false
+	}
+
+	path := d.analyseGhost(start, func(node string) bool { return node == end })
+	return len(path.prefixEnd) > 0 || len(path.cycleEnd) > 0
+}
+
 func (d *Desert) TraverseDesert(start, end string) int {
 	var count int
 	curr := start
@@ -54,6 +98,164 @@ func (d *Desert) TraverseDesert(start, end string) int {
 	return count
 }
 
+// Position of a ghost: the node it stands on and the next instruction
+type ghostState struct {
+	node  string
+	instr *dRingNode
+}
+
+func (d *Desert) step(s ghostState) ghostState {
+	choices, ok := d.directions[s.node]
+	if !ok {
+		log.Fatalf("Direction without mapping")
+	}
+	return ghostState{choices[s.instr.direction], s.instr.next}
+}
+
+// Steps at which a ghost stands on an end node. Ends before the cycle
+// happen once, ends inside it repeat every cycle.Length steps.
+type ghostPath struct {
+	start     string
+	cycle     utils.Cycle
+	prefixEnd []int
+	cycleEnd  []int
+}
+
+func (d *Desert) analyseGhost(start string, end func(string) bool) ghostPath {
+	state := ghostState{start, d.instructions.head}
+	path := ghostPath{start: start}
+	path.cycle = utils.Brent(state, d.step)
+
+	for t := 0; t < path.cycle.Start+path.cycle.Length; t++ {
+		if end(state.node) {
+			if t < path.cycle.Start {
+				path.prefixEnd = append(path.prefixEnd, t)
+			} else {
+				path.cycleEnd = append(path.cycleEnd, t)
+			}
+		}
+		state = d.step(state)
+	}
+
+	slog.Debug("Ghost path", "start", start, "cycle", path.cycle, "prefixEnd", path.prefixEnd, "cycleEnd", path.cycleEnd)
+	return path
+}
+
+// Walks a ghost from every start node at once and returns the first step
+// where all of them stand on end nodes. Each ghost's (node, instruction)
+// states are eventually periodic so the answer comes from combining cycles
+// instead of simulating every step.
+func (d *Desert) TraverseGhosts(start, end func(string) bool) (int, error) {
+	paths := []ghostPath{}
+	for _, node := range d.graph.Nodes() {
+		if start(node) {
+			paths = append(paths, d.analyseGhost(node, end))
+		}
+	}
+	if len(paths) == 0 {
+		return 0, fmt.Errorf("no start nodes")
+	}
+
+	if steps, ok, err := ghostsAlignOnLCM(paths); ok {
+		return steps, err
+	}
+	slog.Info("Ghost cycles do not have a single end at the cycle length, solving with CRT")
+
+	return d.ghostsAlignOnCRT(paths)
+}
+
+// The usual shape of the puzzle: each ghost reaches one end node exactly
+// at every multiple of its cycle length and never before its cycle, so no
+// earlier step can line them up. Reports whether the paths have that shape
+func ghostsAlignOnLCM(paths []ghostPath) (int, bool, error) {
+	lengths := []int{}
+
+	for _, path := range paths {
+		if len(path.prefixEnd) > 0 || len(path.cycleEnd) != 1 || path.cycleEnd[0] != path.cycle.Length {
+			return 0, false, nil
+		}
+		lengths = append(lengths, path.cycle.Length)
+	}
+
+	steps, ok := utils.LCM(lengths...)
+	if !ok {
+		return 0, true, fmt.Errorf("ghosts line up after more steps than fit in an int")
+	}
+	return steps, true, nil
+}
+
+// General case: check the steps before every ghost is in its cycle directly,
+// then solve the congruences for every combination of in-cycle end steps
+func (d *Desert) ghostsAlignOnCRT(paths []ghostPath) (int, error) {
+	const maxCombinations = 1 << 20
+	var lead int
+
+	for _, path := range paths {
+		lead = max(lead, path.cycle.Start)
+	}
+	for t := 0; t < lead; t++ {
+		if ghostsAllEnd(paths, t) {
+			return t, nil
+		}
+	}
+
+	combinations := 1
+	for _, path := range paths {
+		if len(path.cycleEnd) == 0 {
+			return 0, fmt.Errorf("ghost from %v never reaches an end node again", path.start)
+		}
+		combinations *= len(path.cycleEnd)
+		if combinations > maxCombinations {
+			return 0, fmt.Errorf("too many end node combinations to check")
+		}
+	}
+
+	best := -1
+	choice := make([]int, len(paths))
+	for i := 0; i < combinations; i++ {
+		residues, moduli := []int{}, []int{}
+		rest := i
+		for j, path := range paths {
+			choice[j] = rest % len(path.cycleEnd)
+			rest /= len(path.cycleEnd)
+			residues = append(residues, path.cycleEnd[choice[j]])
+			moduli = append(moduli, path.cycle.Length)
+		}
+
+		x, m, ok := utils.CRT(residues, moduli)
+		if !ok {
+			continue
+		}
+		// every residue is only valid once its ghost is inside the cycle
+		if x < lead {
+			x += (lead - x + m - 1) / m * m
+		}
+		if best < 0 || x < best {
+			best = x
+		}
+	}
+
+	if best < 0 {
+		return 0, fmt.Errorf("end node cycles never coincide")
+	}
+	return best, nil
+}
+
+func ghostsAllEnd(paths []ghostPath, t int) bool {
+	for _, path := range paths {
+		if t < path.cycle.Start {
+			if !utils.Contains(path.prefixEnd, t) {
+				return false
+			}
+			continue
+		}
+		if !utils.Contains(path.cycleEnd, path.cycle.Index(t)) {
+			return false
+		}
+	}
+	return true
+}
+
 type DirectionRing struct {
 	head   *dRingNode
 	tail   *dRingNode
@@ -62,6 +264,12 @@ type DirectionRing struct {
 
 type MapInstructions map[string][]string
 
+// Converts the node mapping into a graph where each node's edges keep the
+// left/right order of the original instructions
+func (m MapInstructions) Graph() *graph.Graph[string] {
+	return graph.FromAdjacency(m)
+}
+
 func (r DirectionRing) String() string {
 	var sb strings.Builder
 
@@ -111,19 +319,26 @@ type dRingNode struct {
 }
 
 type WastelandParser struct {
-	scanner *bufio.Scanner
+	sections *utils.SectionScanner
 }
 
-func newWastelandParser(file *os.File) *WastelandParser {
-	p := &WastelandParser{bufio.NewScanner(file)}
+func newWastelandParser(file io.Reader) *WastelandParser {
+	p := &WastelandParser{utils.NewSectionScanner(file)}
+	p.sections.IsHeader = nil
 	return p
 }
 
 func (p *WastelandParser) Parse() (*DirectionRing, MapInstructions) {
-	p.scanner.Scan()
-	ringLine := p.scanner.Text()
+	if !p.sections.Scan() {
+		log.Fatalf("No instructions found: %v\n", p.sections.Err())
+	}
+	ringLine := strings.Join(p.sections.Section().Lines, "")
 	ring := p.parseRing(ringLine)
-	directions := p.parseDirections()
+
+	if !p.sections.Scan() {
+		log.Fatalf("No node mappings found: %v\n", p.sections.Err())
+	}
+	directions := p.parseDirections(p.sections.Section())
 	return ring, directions
 }
 
@@ -136,19 +351,22 @@ func (p *WastelandParser) parseRing(line string) *DirectionRing {
 	return ring
 }
 
-func (p *WastelandParser) parseDirections() MapInstructions {
+var wastelandNode = parse.MustCompile("{name:word} = ({left:word}, {right:word})")
+
+type wastelandLine struct {
+	Name  string
+	Left  string
+	Right string
+}
+
+func (p *WastelandParser) parseDirections(section utils.Section) MapInstructions {
 	mapDirections := make(MapInstructions)
-	for p.scanner.Scan() {
-		line := p.scanner.Text()
-		if line == "" {
-			continue
-		}
-		split := strings.Split(line, " = ")
-		if len(split) != 2 {
-			log.Fatalf("Line not properly formatted: %v\n", line)
+	for i, line := range section.Lines {
+		node := wastelandLine{}
+		if err := wastelandNode.Decode(line, &node); err != nil {
+			log.Fatalf("Line %d not properly formatted: %v\n", section.LineNumber(i), err)
 		}
-		directions := strings.Trim(split[1], "()")
-		mapDirections[split[0]] = strings.Split(directions, ", ")
+		mapDirections[node.Name] = []string{node.Left, node.Right}
 	}
 	slog.Debug("Found these directions", "directions", mapDirections)
 	return mapDirections
@@ -0,0 +1,69 @@
+package solutions
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ryanpdenoux/advent-of-code/utils"
+)
+
+func TestTraverseGhostsPrefix(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{
+			// 11A leaves the end nodes for good after step 1
+			"no end inside the cycle",
+			"L\n\n11A = (11Z, XXX)\n11Z = (11B, XXX)\n11B = (11B, XXX)\n22A = (22Z, XXX)\n22Z = (22Z, XXX)\nXXX = (XXX, XXX)\n",
+			1,
+		},
+		{
+			// both ghosts fit the LCM shape but already line up at step 1
+			"end before the cycle",
+			"L\n\n11A = (11Z, XXX)\n11Z = (12Z, XXX)\n12Z = (11B, XXX)\n11B = (12Z, XXX)\n22A = (22Z, XXX)\n22Z = (22Z, XXX)\nXXX = (XXX, XXX)\n",
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		instructions, directions := newWastelandParser(strings.NewReader(tt.input)).Parse()
+		got, err := newDesert(instructions, directions).TraverseGhosts(isGhostStart, isGhostEnd)
+		if err != nil || got != tt.want {
+			t.Errorf("%v: TraverseGhosts() = %v %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestReaches(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"example", "LLR\n\nAAA = (BBB, BBB)\nBBB = (AAA, ZZZ)\nZZZ = (ZZZ, ZZZ)\n", true},
+		// ZZZ is a neighbour of AAA but always going left never takes it
+		{"reachable but never walked", "L\n\nAAA = (BBB, ZZZ)\nBBB = (AAA, AAA)\n", false},
+		{"no AAA", "L\n\n11A = (11Z, 11Z)\n11Z = (11A, 11A)\n", false},
+	}
+
+	for _, tt := range tests {
+		instructions, directions := newWastelandParser(strings.NewReader(tt.input)).Parse()
+		if got := newDesert(instructions, directions).Reaches("AAA", "ZZZ"); got != tt.want {
+			t.Errorf("%v: Reaches(AAA, ZZZ) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGhostsAlignOnLCMOverflow(t *testing.T) {
+	paths := []ghostPath{}
+	for _, length := range []int{math.MaxInt32, math.MaxInt32 - 1, math.MaxInt32 - 2} {
+		paths = append(paths, ghostPath{cycle: utils.Cycle{Start: 1, Length: length}, cycleEnd: []int{length}})
+	}
+
+	if steps, ok, err := ghostsAlignOnLCM(paths); !ok || err == nil {
+		t.Errorf("ghostsAlignOnLCM() = %v %v %v, want an overflow error", steps, ok, err)
+	}
+}
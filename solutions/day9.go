@@ -2,57 +2,169 @@ package solutions
 
 import (
 	"bufio"
-	"fmt"
+	"flag"
+	"io"
 	"log"
 	"log/slog"
-	"os"
+	"math/big"
+	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/ryanpdenoux/advent-of-code/utils/poly"
+)
+
+var oasisExact = flag.Bool(
+	"oasis-exact",
+	false,
+	"predict Day9 values with exact rational polynomials instead of int differences",
 )
 
-func Day9(file *os.File) {
+// Rows of integers
+var Day9Signature = Signature{
+	Line: regexp.MustCompile(`^-?\d+( -?\d+)*$`),
+}
+
+func Day9(file io.Reader) []Answer {
 	var sum int
+	var previous int
 
 	parser := newOasisParser(file)
 	records := parser.Parse()
+
+	if *oasisExact {
+		sumExact, previousExact := new(big.Rat), new(big.Rat)
+		for _, record := range records {
+			sumExact.Add(sumExact, record.PredictExact(int64(len(record))))
+			previousExact.Add(previousExact, record.PredictExact(-1))
+		}
+
+		return []Answer{
+			newAnswer(1, "Sum of predicted values", sumExact.RatString()),
+			newAnswer(2, "Sum of predicted previous values", previousExact.RatString()),
+		}
+	}
+
 	for _, record := range records {
 		sum += record.Predict()
+		previous += record.PredictPrevious()
 	}
 
-	fmt.Printf("Sum of predicted values: %v\n", sum)
+	return []Answer{
+		newAnswer(1, "Sum of predicted values", sum),
+		newAnswer(2, "Sum of predicted previous values", previous),
+	}
 }
 
 type OasisRecord []int
 
-func (r *OasisRecord) Predict() int {
+// Next value after the record
+func (r OasisRecord) Predict() int {
+	var prediction int
+
+	table := r.Differences()
+	prediction = table.At(len(r))
+	slog.Debug("Predicted value for current record", "record", r, "prediction", prediction, "degree", table.Degree())
+
+	return prediction
+}
+
+// Value that would come right before the record
+func (r OasisRecord) PredictPrevious() int {
 	var prediction int
 
-	prediction = r.predictValue()
-	slog.Debug("Predicted value for current record", "record", r, "prediction", prediction)
+	table := r.Differences()
+	prediction = table.At(-1)
+	slog.Debug("Predicted previous value for current record", "record", r, "prediction", prediction, "degree", table.Degree())
+
+	return prediction
+}
+
+// Finite differences of the record, evaluates the record's polynomial anywhere
+func (r OasisRecord) Differences() DifferenceTable {
+	return newDifferenceTable(r)
+}
+
+// Degree of the polynomial the record follows, -1 for a record of all zeros
+func (r OasisRecord) Degree() int {
+	return r.Differences().Degree()
+}
+
+// Exact polynomial through the record, indexed from 0
+func (r OasisRecord) Polynomial() poly.Polynomial {
+	return poly.FitSequence(r)
+}
+
+// Evaluates the record's polynomial at index x without overflowing
+func (r OasisRecord) PredictExact(x int64) *big.Rat {
+	p := r.Polynomial()
+	prediction := p.EvalInt(x)
+	slog.Debug("Predicted exact value for current record", "record", r, "x", x, "prediction", prediction.RatString(), "polynomial", p)
 
 	return prediction
 }
 
-func (r OasisRecord) predictValue() int {
-	new := OasisRecord{}
-	curr := r[0]
-	zero := true
+// First entry of every row of finite differences. Together they describe
+// the polynomial through the record, f(x) = sum C(x, j) * heads[j].
+type DifferenceTable struct {
+	heads []int
+	// whether a row of zeros was reached, otherwise the record was too
+	// short to pin down its polynomial
+	exact bool
+}
+
+func newDifferenceTable(r OasisRecord) DifferenceTable {
+	table := DifferenceTable{}
+	row := make([]int, len(r))
+	copy(row, r)
 
-	for i := 1; i < len(r); i++ {
-		next := r[i]
-		val := next - curr
-		new = append(new, val)
-		curr = next
-		if curr != 0 {
-			zero = false
+	// each pass replaces row in place with its differences
+	for len(row) > 0 {
+		if allZero(row) {
+			table.exact = true
+			break
 		}
+		table.heads = append(table.heads, row[0])
+		for i := 0; i < len(row)-1; i++ {
+			row[i] = row[i+1] - row[i]
+		}
+		row = row[:len(row)-1]
 	}
 
-	if zero {
-		return 0
+	if !table.exact {
+		slog.Warn("Differences never reached zero, extrapolation may be off", "record", r)
+	}
+	return table
+}
+
+func allZero(row []int) bool {
+	for _, val := range row {
+		if val != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Degree of the polynomial, -1 for a record of all zeros
+func (t DifferenceTable) Degree() int {
+	return len(t.heads) - 1
+}
+
+// Evaluates the polynomial at index x of the record, x may be negative or
+// past the end
+func (t DifferenceTable) At(x int) int {
+	var value int
+	binomial := 1 // C(x, j), exact for any integer x
+
+	for j, head := range t.heads {
+		if j > 0 {
+			binomial = binomial * (x - j + 1) / j
+		}
+		value += binomial * head
 	}
 
-	return curr + new.predictValue()
+	return value
 }
 
 // Parsing
@@ -60,7 +172,7 @@ type OasisParser struct {
 	scanner *bufio.Scanner
 }
 
-func newOasisParser(file *os.File) *OasisParser {
+func newOasisParser(file io.Reader) *OasisParser {
 	parser := &OasisParser{
 		scanner: bufio.NewScanner(file),
 	}
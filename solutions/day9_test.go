@@ -0,0 +1,41 @@
+package solutions
+
+import "testing"
+
+func TestOasisRecordDegree(t *testing.T) {
+	tests := []struct {
+		record OasisRecord
+		degree int
+	}{
+		{OasisRecord{0, 3, 6, 9, 12, 15}, 1},
+		{OasisRecord{1, 3, 6, 10, 15, 21}, 2},
+		{OasisRecord{10, 13, 16, 21, 30, 45}, 3},
+		{OasisRecord{5, 5, 5}, 0},
+		{OasisRecord{0, 0}, -1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.record.Degree(); got != tt.degree {
+			t.Errorf("%v.Degree() = %v, want %v", tt.record, got, tt.degree)
+		}
+	}
+}
+
+func TestPredictExactMatchesDifferences(t *testing.T) {
+	records := []OasisRecord{
+		{0, 3, 6, 9, 12, 15},
+		{1, 3, 6, 10, 15, 21},
+		{10, 13, 16, 21, 30, 45},
+		{-4, 2, 17, 45, 88, 146, 217},
+	}
+
+	for _, record := range records {
+		table := record.Differences()
+		for _, x := range []int{-1, len(record)} {
+			exact := record.PredictExact(int64(x))
+			if !exact.IsInt() || exact.Num().Int64() != int64(table.At(x)) {
+				t.Errorf("%v at %v: PredictExact = %v, DifferenceTable.At = %v", record, x, exact.RatString(), table.At(x))
+			}
+		}
+	}
+}
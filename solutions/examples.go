@@ -0,0 +1,58 @@
+package solutions
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+// Example inputs from the puzzle texts, named day<day>-<n>.txt
+//
+//go:embed examples
+var examples embed.FS
+
+// Opens the n-th (1-based) example input of a day
+func Example(day, n int) (fs.File, error) {
+	file, err := examples.Open(examplePath(day, n))
+	if err != nil {
+		return nil, fmt.Errorf("day %d has no example %d (it has %d)", day, n, ExampleCount(day))
+	}
+	return file, nil
+}
+
+// Number of embedded examples for a day
+func ExampleCount(day int) int {
+	count := 0
+	for {
+		if _, err := fs.Stat(examples, examplePath(day, count+1)); err != nil {
+			return count
+		}
+		count++
+	}
+}
+
+// Answers the puzzle text gives for an example by part, parts it does not
+// give are missing
+func ExampleAnswers(day, n int) map[int]string {
+	answers := exampleAnswers[day]
+	if n < 1 || n > len(answers) {
+		return nil
+	}
+	return answers[n-1]
+}
+
+var exampleAnswers = map[int][]map[int]string{
+	1: {{1: "142"}, {2: "281"}},
+	2: {{1: "8", 2: "2286"}},
+	3: {{1: "4361", 2: "467835"}},
+	4: {{1: "13", 2: "30"}},
+	5: {{1: "35", 2: "46"}},
+	6: {{1: "288", 2: "71503"}},
+	7: {{1: "6440", 2: "5905"}},
+	8: {{1: "2"}, {1: "6"}, {2: "6"}},
+	9: {{1: "114", 2: "2"}},
+}
+
+func examplePath(day, n int) string {
+	return fmt.Sprintf("examples/day%d-%d.txt", day, n)
+}
@@ -0,0 +1,51 @@
+package solutions
+
+import (
+	"io"
+	"testing"
+)
+
+var exampleSolvers = map[int]func(io.Reader) []Answer{
+	1: Day1,
+	2: Day2,
+	3: Day3,
+	4: Day4,
+	5: Day5,
+	6: Day6,
+	7: Day7,
+	8: Day8,
+	9: Day9,
+}
+
+func TestExamples(t *testing.T) {
+	for day, solve := range exampleSolvers {
+		if ExampleCount(day) == 0 {
+			t.Errorf("day %d has no embedded examples", day)
+		}
+
+		for n := 1; n <= ExampleCount(day); n++ {
+			expected := ExampleAnswers(day, n)
+			if len(expected) == 0 {
+				t.Errorf("day %d example %d has no expected answers", day, n)
+				continue
+			}
+
+			file, err := Example(day, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			answers := solve(file)
+			file.Close()
+
+			got := map[int]string{}
+			for _, answer := range answers {
+				got[answer.Part] = answer.Value
+			}
+			for part, want := range expected {
+				if got[part] != want {
+					t.Errorf("day %d example %d part %d = %q, want %q", day, n, part, got[part], want)
+				}
+			}
+		}
+	}
+}
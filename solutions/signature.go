@@ -0,0 +1,112 @@
+package solutions
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rough shape of a day's input, used to catch running a day on another
+// day's file before the parser fails somewhere deep. Empty fields are not
+// checked
+type Signature struct {
+	// First line starts with this
+	Prefix string
+	// Every non blank line matches this
+	Line *regexp.Regexp
+}
+
+// Error for input that does not fit a Signature
+type SignatureError struct {
+	Line   int
+	Text   string
+	Reason string
+}
+
+func (e *SignatureError) Error() string {
+	if e.Line == 0 {
+		return e.Reason
+	}
+	return fmt.Sprintf("line %d %q %s", e.Line, truncate(e.Text, 40), e.Reason)
+}
+
+// Reports the first line of data that does not fit the signature
+func (s Signature) Check(data []byte) error {
+	lines, err := signatureLines(data)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return &SignatureError{Reason: "input is empty"}
+	}
+
+	for i, line := range lines {
+		number := i + 1
+
+		if number == 1 && !strings.HasPrefix(line, s.Prefix) {
+			return &SignatureError{number, line, fmt.Sprintf("does not start with %q", s.Prefix)}
+		}
+		if s.Line != nil && strings.TrimSpace(line) != "" && !s.Line.MatchString(line) {
+			return &SignatureError{number, line, fmt.Sprintf("does not match %s", s.Line)}
+		}
+	}
+
+	return nil
+}
+
+// How well data fits the signature from 0 to 1, the share of non blank
+// lines matching Line, halved when the first line misses Prefix. An empty
+// signature scores 0 since it says nothing about the input
+func (s Signature) Score(data []byte) float64 {
+	if s.Prefix == "" && s.Line == nil {
+		return 0
+	}
+
+	lines, err := signatureLines(data)
+	if err != nil || len(lines) == 0 {
+		return 0
+	}
+
+	total, matched := 0, 0
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		total++
+		if s.Line == nil || s.Line.MatchString(line) {
+			matched++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	score := float64(matched) / float64(total)
+	if !strings.HasPrefix(lines[0], s.Prefix) {
+		score /= 2
+	}
+	return score
+}
+
+// Splits data the way the solvers read it, so a CRLF file loses its '\r'
+// before any line is matched
+func signatureLines(data []byte) ([]string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+
+	lines := []string{}
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	return lines, scanner.Err()
+}
+
+func truncate(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+	return text[:length] + "..."
+}
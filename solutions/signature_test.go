@@ -0,0 +1,27 @@
+package solutions
+
+import "testing"
+
+func TestSignatureLineEndings(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		score float64
+		ok    bool
+	}{
+		{"LF", "Time:      7  15   30\nDistance:  9  40  200\n", 1, true},
+		{"CRLF", "Time:      7  15   30\r\nDistance:  9  40  200\r\n", 1, true},
+		{"no trailing newline", "Time: 7\r\nDistance: 9", 1, true},
+		{"wrong day", "Game 1: 3 blue\nGame 2: 1 red\n", 0, false},
+		{"empty", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		if score := Day6Signature.Score([]byte(tt.input)); score != tt.score {
+			t.Errorf("%v: Score() = %v, want %v", tt.name, score, tt.score)
+		}
+		if err := Day6Signature.Check([]byte(tt.input)); (err == nil) != tt.ok {
+			t.Errorf("%v: Check() = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
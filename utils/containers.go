@@ -0,0 +1,224 @@
+package utils
+
+// Handle to a value held in a PriorityQueue, used to change its priority
+type PQItem[T any] struct {
+	Value T
+	index int
+}
+
+// Binary heap ordered by less; pass a "greater" func for a max queue
+type PriorityQueue[T any] struct {
+	items []*PQItem[T]
+	less  func(a, b T) bool
+}
+
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	q := &PriorityQueue[T]{less: less}
+	return q
+}
+
+func (q *PriorityQueue[T]) Len() int {
+	return len(q.items)
+}
+
+// Adds value to the queue and returns a handle for later updates
+func (q *PriorityQueue[T]) Push(value T) *PQItem[T] {
+	item := &PQItem[T]{Value: value, index: len(q.items)}
+	q.items = append(q.items, item)
+	q.up(item.index)
+	return item
+}
+
+// Removes and returns the value that sorts first
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	item := q.remove(0)
+	return item.Value, true
+}
+
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.items[0].Value, true
+}
+
+// Replaces the value of a queued item and restores heap order. Covers
+// decrease-key as well as increasing a priority.
+func (q *PriorityQueue[T]) Update(item *PQItem[T], value T) {
+	if !q.Contains(item) {
+		return
+	}
+	item.Value = value
+	if !q.up(item.index) {
+		q.down(item.index)
+	}
+}
+
+// Drops an item from the queue, reporting whether it was still queued
+func (q *PriorityQueue[T]) Remove(item *PQItem[T]) bool {
+	if !q.Contains(item) {
+		return false
+	}
+	q.remove(item.index)
+	return true
+}
+
+func (q *PriorityQueue[T]) Contains(item *PQItem[T]) bool {
+	return item != nil && item.index >= 0 && item.index < len(q.items) && q.items[item.index] == item
+}
+
+func (q *PriorityQueue[T]) remove(i int) *PQItem[T] {
+	last := len(q.items) - 1
+	item := q.items[i]
+
+	q.swap(i, last)
+	q.items[last] = nil
+	q.items = q.items[:last]
+	if i < last && !q.up(i) {
+		q.down(i)
+	}
+
+	item.index = -1
+	return item
+}
+
+func (q *PriorityQueue[T]) swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+// Moves item i towards the root, reporting whether it moved
+func (q *PriorityQueue[T]) up(i int) bool {
+	start := i
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i].Value, q.items[parent].Value) {
+			break
+		}
+		q.swap(i, parent)
+		i = parent
+	}
+	return i != start
+}
+
+func (q *PriorityQueue[T]) down(i int) {
+	n := len(q.items)
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < n && q.less(q.items[left].Value, q.items[smallest].Value) {
+			smallest = left
+		}
+		if right < n && q.less(q.items[right].Value, q.items[smallest].Value) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		q.swap(i, smallest)
+		i = smallest
+	}
+}
+
+// Double ended queue backed by a ring buffer that doubles when full
+type Deque[T any] struct {
+	buf    []T
+	head   int
+	length int
+}
+
+func NewDeque[T any](capacity int) *Deque[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	d := &Deque[T]{buf: make([]T, capacity)}
+	return d
+}
+
+func (d *Deque[T]) Len() int {
+	return d.length
+}
+
+func (d *Deque[T]) PushBack(value T) {
+	d.grow()
+	d.buf[d.wrap(d.head+d.length)] = value
+	d.length++
+}
+
+func (d *Deque[T]) PushFront(value T) {
+	d.grow()
+	d.head = d.wrap(d.head - 1)
+	d.buf[d.head] = value
+	d.length++
+}
+
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.length == 0 {
+		return zero, false
+	}
+
+	value := d.buf[d.head]
+	d.buf[d.head] = zero
+	d.head = d.wrap(d.head + 1)
+	d.length--
+	return value, true
+}
+
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.length == 0 {
+		return zero, false
+	}
+
+	tail := d.wrap(d.head + d.length - 1)
+	value := d.buf[tail]
+	d.buf[tail] = zero
+	d.length--
+	return value, true
+}
+
+func (d *Deque[T]) Front() (T, bool) {
+	return d.At(0)
+}
+
+func (d *Deque[T]) Back() (T, bool) {
+	return d.At(d.length - 1)
+}
+
+// Returns the i-th element counting from the front
+func (d *Deque[T]) At(i int) (T, bool) {
+	if i < 0 || i >= d.length {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.wrap(d.head+i)], true
+}
+
+func (d *Deque[T]) wrap(i int) int {
+	n := len(d.buf)
+	return ((i % n) + n) % n
+}
+
+func (d *Deque[T]) grow() {
+	if d.buf == nil {
+		d.buf = make([]T, 1)
+	}
+	if d.length < len(d.buf) {
+		return
+	}
+
+	buf := make([]T, len(d.buf)*2)
+	for i := 0; i < d.length; i++ {
+		buf[i] = d.buf[d.wrap(d.head+i)]
+	}
+	d.buf = buf
+	d.head = 0
+}
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		// index into values: new priority, or -1 to remove the item
+		changes map[int]int
+		want    []int
+	}{
+		{"plain", []int{5, 1, 4, 2, 3}, nil, []int{1, 2, 3, 4, 5}},
+		{"decrease key", []int{5, 1, 4}, map[int]int{0: 0}, []int{0, 1, 4}},
+		{"increase key", []int{5, 1, 4}, map[int]int{1: 9}, []int{4, 5, 9}},
+		{"remove root", []int{5, 1, 4}, map[int]int{1: -1}, []int{4, 5}},
+		{"remove last", []int{1, 2, 3}, map[int]int{2: -1}, []int{1, 2}},
+		{"duplicates", []int{2, 2, 1, 2}, map[int]int{2: 3}, []int{2, 2, 2, 3}},
+		{"empty", nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		q := NewPriorityQueue(func(a, b int) bool { return a < b })
+		items := []*PQItem[int]{}
+		for _, value := range tt.values {
+			items = append(items, q.Push(value))
+		}
+		for i, value := range tt.changes {
+			if value < 0 {
+				q.Remove(items[i])
+				continue
+			}
+			q.Update(items[i], value)
+		}
+
+		var got []int
+		for q.Len() > 0 {
+			value, _ := q.Pop()
+			got = append(got, value)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%v: pop order = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPriorityQueueRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	q := NewPriorityQueue(func(a, b int) bool { return a > b })
+	queued := map[*PQItem[int]]bool{}
+	items := []*PQItem[int]{}
+
+	for i := 0; i < 1000; i++ {
+		switch item := rng.Intn(len(items) + 1); {
+		case item == len(items) || rng.Intn(3) == 0:
+			pushed := q.Push(rng.Intn(100))
+			items = append(items, pushed)
+			queued[pushed] = true
+		case rng.Intn(2) == 0:
+			q.Update(items[item], rng.Intn(100))
+		default:
+			if removed := q.Remove(items[item]); removed != queued[items[item]] {
+				t.Fatalf("Remove() = %v for an item queued %v", removed, queued[items[item]])
+			}
+			queued[items[item]] = false
+		}
+	}
+
+	want := []int{}
+	for item, ok := range queued {
+		if ok {
+			want = append(want, item.Value)
+		}
+	}
+	slices.Sort(want)
+	slices.Reverse(want)
+
+	got := []int{}
+	for q.Len() > 0 {
+		value, _ := q.Pop()
+		got = append(got, value)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("pop order = %v, want %v", got, want)
+	}
+	if _, ok := q.Pop(); ok {
+		t.Errorf("Pop() on an empty queue succeeded")
+	}
+	if q.Remove(items[0]) || q.Contains(items[0]) {
+		t.Errorf("popped item is still queued")
+	}
+}
+
+func TestDequeWraparound(t *testing.T) {
+	d := NewDeque[int](4)
+	for i := 1; i <= 3; i++ {
+		d.PushBack(i)
+	}
+	d.PopFront()
+	d.PopFront()
+	// head sits at index 2, these wrap around the end of the buffer
+	d.PushBack(4)
+	d.PushBack(5)
+	d.PushFront(2)
+	// full, the next push doubles the buffer
+	d.PushBack(6)
+	d.PushFront(1)
+
+	got := []int{}
+	for i := 0; i < d.Len(); i++ {
+		value, _ := d.At(i)
+		got = append(got, value)
+	}
+	if want := []int{1, 2, 3, 4, 5, 6}; !slices.Equal(got, want) {
+		t.Errorf("contents = %v, want %v", got, want)
+	}
+	if front, _ := d.Front(); front != 1 {
+		t.Errorf("Front() = %v, want 1", front)
+	}
+	if back, _ := d.Back(); back != 6 {
+		t.Errorf("Back() = %v, want 6", back)
+	}
+	if _, ok := d.At(6); ok {
+		t.Errorf("At(6) succeeded past the end")
+	}
+}
+
+func TestDequeRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, capacity := range []int{0, 1, 3, 16} {
+		d := NewDeque[int](capacity)
+		model := []int{}
+
+		for i := 0; i < 2000; i++ {
+			switch rng.Intn(4) {
+			case 0:
+				d.PushBack(i)
+				model = append(model, i)
+			case 1:
+				d.PushFront(i)
+				model = append([]int{i}, model...)
+			case 2:
+				value, ok := d.PopFront()
+				if ok != (len(model) > 0) || ok && value != model[0] {
+					t.Fatalf("capacity %d step %d: PopFront() = %v %v, want %v", capacity, i, value, ok, model)
+				}
+				if ok {
+					model = model[1:]
+				}
+			case 3:
+				value, ok := d.PopBack()
+				if ok != (len(model) > 0) || ok && value != model[len(model)-1] {
+					t.Fatalf("capacity %d step %d: PopBack() = %v %v, want %v", capacity, i, value, ok, model)
+				}
+				if ok {
+					model = model[:len(model)-1]
+				}
+			}
+
+			if d.Len() != len(model) {
+				t.Fatalf("capacity %d step %d: Len() = %v, want %v", capacity, i, d.Len(), len(model))
+			}
+		}
+
+		for i, want := range model {
+			if got, _ := d.At(i); got != want {
+				t.Errorf("capacity %d: At(%d) = %v, want %v", capacity, i, got, want)
+			}
+		}
+	}
+}
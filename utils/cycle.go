@@ -0,0 +1,88 @@
+package utils
+
+// Describes an eventually periodic sequence x0, f(x0), f(f(x0)), ...
+// Start is the index of the first state that repeats and Length is the
+// number of steps between repeats.
+type Cycle struct {
+	Start  int
+	Length int
+}
+
+// Maps step n onto the earliest step that produces the same state
+func (c Cycle) Index(n int) int {
+	if n < c.Start || c.Length == 0 {
+		return n
+	}
+	return c.Start + (n-c.Start)%c.Length
+}
+
+// Finds the cycle using Floyd's tortoise and hare. step must be
+// deterministic and the state space finite, otherwise this never returns.
+func Floyd[S comparable](x0 S, step func(S) S) Cycle {
+	tortoise := step(x0)
+	hare := step(step(x0))
+	for tortoise != hare {
+		tortoise = step(tortoise)
+		hare = step(step(hare))
+	}
+
+	var start int
+	tortoise = x0
+	for tortoise != hare {
+		tortoise = step(tortoise)
+		hare = step(hare)
+		start++
+	}
+
+	length := 1
+	hare = step(tortoise)
+	for tortoise != hare {
+		hare = step(hare)
+		length++
+	}
+
+	return Cycle{Start: start, Length: length}
+}
+
+// Finds the cycle using Brent's algorithm, which needs fewer calls to step
+// than Floyd. The same termination caveats apply.
+func Brent[S comparable](x0 S, step func(S) S) Cycle {
+	power, length := 1, 1
+	tortoise := x0
+	hare := step(x0)
+	for tortoise != hare {
+		if power == length {
+			tortoise = hare
+			power *= 2
+			length = 0
+		}
+		hare = step(hare)
+		length++
+	}
+
+	tortoise, hare = x0, x0
+	for i := 0; i < length; i++ {
+		hare = step(hare)
+	}
+
+	var start int
+	for tortoise != hare {
+		tortoise = step(tortoise)
+		hare = step(hare)
+		start++
+	}
+
+	return Cycle{Start: start, Length: length}
+}
+
+// Returns the state after n steps without simulating every one of them
+func FastForward[S comparable](x0 S, step func(S) S, n int) S {
+	cycle := Brent(x0, step)
+	state := x0
+
+	for i := cycle.Index(n); i > 0; i-- {
+		state = step(state)
+	}
+
+	return state
+}
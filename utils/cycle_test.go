@@ -0,0 +1,98 @@
+package utils
+
+import "testing"
+
+// 0, 1, ..., start+length-1 then back to start
+func rho(start, length int) func(int) int {
+	return func(x int) int {
+		if x+1 < start+length {
+			return x + 1
+		}
+		return start
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	tests := []Cycle{
+		{0, 1},
+		{0, 5},
+		{3, 1},
+		{3, 4},
+		{10, 7},
+		{1, 64},
+	}
+
+	finders := map[string]func(int, func(int) int) Cycle{
+		"Floyd": Floyd[int],
+		"Brent": Brent[int],
+	}
+
+	for name, find := range finders {
+		for _, want := range tests {
+			calls := 0
+			step := rho(want.Start, want.Length)
+			counted := func(x int) int {
+				calls++
+				return step(x)
+			}
+
+			if got := find(0, counted); got != want {
+				t.Errorf("%v(rho%+v) = %+v", name, want, got)
+			}
+			if limit := 6 * (want.Start + want.Length); calls > limit {
+				t.Errorf("%v(rho%+v) stepped %d times, want at most %d", name, want, calls, limit)
+			}
+		}
+	}
+}
+
+func TestCycleIndex(t *testing.T) {
+	tests := []struct {
+		cycle Cycle
+		n     int
+		want  int
+	}{
+		{Cycle{3, 4}, 0, 0},
+		{Cycle{3, 4}, 2, 2},
+		{Cycle{3, 4}, 3, 3},
+		{Cycle{3, 4}, 7, 3},
+		{Cycle{3, 4}, 1_000_000_002, 6},
+		{Cycle{0, 5}, 12, 2},
+		{Cycle{}, 9, 9},
+	}
+
+	for _, tt := range tests {
+		if got := tt.cycle.Index(tt.n); got != tt.want {
+			t.Errorf("%+v.Index(%v) = %v, want %v", tt.cycle, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFastForward(t *testing.T) {
+	tests := []struct {
+		start, length int
+		n             int
+	}{
+		// n before the cycle starts
+		{10, 7, 0},
+		{10, 7, 4},
+		{10, 7, 10},
+		{10, 7, 123},
+		// the very first state repeats
+		{0, 5, 0},
+		{0, 5, 5},
+		{0, 5, 1_000_000_003},
+	}
+
+	for _, tt := range tests {
+		step := rho(tt.start, tt.length)
+
+		want := tt.n
+		if want >= tt.start {
+			want = tt.start + (tt.n-tt.start)%tt.length
+		}
+		if got := FastForward(0, step, tt.n); got != want {
+			t.Errorf("FastForward(rho(%v, %v), %v) = %v, want %v", tt.start, tt.length, tt.n, got, want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrCycle = errors.New("graph contains a cycle")
+
+// Weighted edge leading away from a node
+type Edge[T comparable] struct {
+	To     T
+	Weight int
+}
+
+// Directed graph stored as an adjacency list. Edges keep their insertion
+// order so positional neighbours (e.g. left/right choices) can be recovered.
+type Graph[T comparable] struct {
+	nodes []T
+	edges map[T][]Edge[T]
+}
+
+func New[T comparable]() *Graph[T] {
+	g := &Graph[T]{
+		edges: make(map[T][]Edge[T]),
+	}
+	return g
+}
+
+// Builds an unweighted graph (every edge has weight 1) from an adjacency list.
+// Nodes are added in sorted order so traversals do not depend on map order
+func FromAdjacency[T cmp.Ordered](adjacency map[T][]T) *Graph[T] {
+	g := New[T]()
+
+	nodes := make([]T, 0, len(adjacency))
+	for node := range adjacency {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+
+	for _, from := range nodes {
+		g.AddNode(from)
+		for _, to := range adjacency[from] {
+			g.AddEdge(from, to, 1)
+		}
+	}
+
+	return g
+}
+
+func (g *Graph[T]) String() string {
+	var sb strings.Builder
+
+	for _, node := range g.nodes {
+		sb.WriteString(fmt.Sprintf("%v -> %v\n", node, g.Neighbours(node)))
+	}
+
+	return sb.String()
+}
+
+func (g *Graph[T]) AddNode(node T) {
+	if g.Has(node) {
+		return
+	}
+	g.nodes = append(g.nodes, node)
+	g.edges[node] = []Edge[T]{}
+}
+
+// Adds a directed edge, creating either endpoint if it does not exist yet
+func (g *Graph[T]) AddEdge(from, to T, weight int) {
+	g.AddNode(from)
+	g.AddNode(to)
+	g.edges[from] = append(g.edges[from], Edge[T]{To: to, Weight: weight})
+}
+
+func (g *Graph[T]) Has(node T) bool {
+	_, ok := g.edges[node]
+	return ok
+}
+
+func (g *Graph[T]) Len() int {
+	return len(g.nodes)
+}
+
+func (g *Graph[T]) Nodes() []T {
+	nodes := make([]T, len(g.nodes))
+	copy(nodes, g.nodes)
+	return nodes
+}
+
+func (g *Graph[T]) Edges(node T) []Edge[T] {
+	return g.edges[node]
+}
+
+func (g *Graph[T]) Neighbours(node T) []T {
+	neighbours := []T{}
+
+	for _, edge := range g.edges[node] {
+		neighbours = append(neighbours, edge.To)
+	}
+
+	return neighbours
+}
+
+// Returns a new graph with every edge reversed
+func (g *Graph[T]) Reverse() *Graph[T] {
+	r := New[T]()
+
+	for _, node := range g.nodes {
+		r.AddNode(node)
+	}
+	for _, from := range g.nodes {
+		for _, edge := range g.edges[from] {
+			r.AddEdge(edge.To, from, edge.Weight)
+		}
+	}
+
+	return r
+}
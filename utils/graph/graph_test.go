@@ -0,0 +1,223 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// a -1-> b -2-> c -1-> d, with the detours a -4-> c and b -5-> d, and an
+// isolated e
+func weighted() *Graph[string] {
+	g := New[string]()
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 4)
+	g.AddEdge("b", "c", 2)
+	g.AddEdge("b", "d", 5)
+	g.AddEdge("c", "d", 1)
+	g.AddNode("e")
+	return g
+}
+
+func TestFromAdjacencyOrder(t *testing.T) {
+	adjacency := map[string][]string{
+		"c": {"a"},
+		"a": {"b", "c"},
+		"b": {"d"},
+	}
+
+	for i := 0; i < 10; i++ {
+		g := FromAdjacency(adjacency)
+		if got, want := g.Nodes(), []string{"a", "b", "c", "d"}; !slices.Equal(got, want) {
+			t.Fatalf("Nodes() = %v, want %v", got, want)
+		}
+		if got, want := g.Neighbours("a"), []string{"b", "c"}; !slices.Equal(got, want) {
+			t.Fatalf("Neighbours(a) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBFS(t *testing.T) {
+	tests := []struct {
+		start  string
+		nodes  []string
+		depths []int
+	}{
+		{"a", []string{"a", "b", "c", "d"}, []int{0, 1, 1, 2}},
+		{"c", []string{"c", "d"}, []int{0, 1}},
+		{"e", []string{"e"}, []int{0}},
+		{"missing", nil, nil},
+	}
+
+	for _, tt := range tests {
+		var nodes []string
+		var depths []int
+		weighted().BFS(tt.start, func(node string, depth int) bool {
+			nodes = append(nodes, node)
+			depths = append(depths, depth)
+			return true
+		})
+
+		if !slices.Equal(nodes, tt.nodes) || !slices.Equal(depths, tt.depths) {
+			t.Errorf("BFS(%v) = %v %v, want %v %v", tt.start, nodes, depths, tt.nodes, tt.depths)
+		}
+	}
+}
+
+func TestDFS(t *testing.T) {
+	g := New[int]()
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(1, 5, 1)
+	g.AddEdge(2, 3, 1)
+	g.AddEdge(2, 4, 1)
+	g.AddEdge(3, 1, 1)
+	g.AddEdge(5, 4, 1)
+
+	tests := []struct {
+		start int
+		stop  int
+		want  []int
+	}{
+		{1, 0, []int{1, 2, 3, 4, 5}},
+		{5, 0, []int{5, 4}},
+		{1, 3, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		var got []int
+		g.DFS(tt.start, func(node int) bool {
+			got = append(got, node)
+			return node != tt.stop
+		})
+
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("DFS(%v) stopping at %v = %v, want %v", tt.start, tt.stop, got, tt.want)
+		}
+	}
+}
+
+func TestBFSPath(t *testing.T) {
+	path, ok := weighted().BFSPath("a", func(node string) bool { return node == "d" })
+	if !ok || !slices.Equal(path, []string{"a", "b", "d"}) {
+		t.Errorf("BFSPath(a, d) = %v %v, want [a b d] true", path, ok)
+	}
+
+	if path, ok := weighted().BFSPath("a", func(node string) bool { return node == "e" }); ok {
+		t.Errorf("BFSPath(a, e) = %v, want no path", path)
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	dist, prev := weighted().Dijkstra("a")
+
+	want := map[string]int{"a": 0, "b": 1, "c": 3, "d": 4}
+	if len(dist) != len(want) {
+		t.Fatalf("Dijkstra(a) = %v, want %v", dist, want)
+	}
+	for node, cost := range want {
+		if dist[node] != cost {
+			t.Errorf("dist[%v] = %v, want %v", node, dist[node], cost)
+		}
+	}
+	if prev["d"] != "c" || prev["c"] != "b" {
+		t.Errorf("prev = %v, want d <- c <- b", prev)
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	// consistent: never more than an edge's weight above its neighbour
+	toD := map[string]int{"a": 4, "b": 3, "c": 1, "d": 0, "e": 0}
+	heuristics := map[string]Heuristic[string]{
+		"zero":  Zero[string],
+		"exact": func(node string) int { return toD[node] },
+	}
+
+	tests := []struct {
+		start, goal string
+		path        []string
+		cost        int
+		ok          bool
+	}{
+		{"a", "d", []string{"a", "b", "c", "d"}, 4, true},
+		{"b", "d", []string{"b", "c", "d"}, 3, true},
+		{"a", "a", []string{"a"}, 0, true},
+		{"a", "e", nil, 0, false},
+		{"d", "a", nil, 0, false},
+		{"missing", "a", nil, 0, false},
+	}
+
+	for name, heuristic := range heuristics {
+		for _, tt := range tests {
+			path, cost, ok := weighted().AStar(tt.start, tt.goal, heuristic)
+			if ok != tt.ok || cost != tt.cost || !slices.Equal(path, tt.path) {
+				t.Errorf("AStar(%v, %v, %v) = %v %v %v, want %v %v %v",
+					tt.start, tt.goal, name, path, cost, ok, tt.path, tt.cost, tt.ok)
+			}
+		}
+	}
+
+	path, cost, ok := weighted().ShortestPath("a", "d")
+	if !ok || cost != 4 || !slices.Equal(path, []string{"a", "b", "c", "d"}) {
+		t.Errorf("ShortestPath(a, d) = %v %v %v", path, cost, ok)
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	order, err := weighted().TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort() error = %v", err)
+	}
+	if want := []string{"a", "e", "b", "c", "d"}; !slices.Equal(order, want) {
+		t.Errorf("TopologicalSort() = %v, want %v", order, want)
+	}
+
+	cyclic := weighted()
+	cyclic.AddEdge("d", "b", 1)
+	if order, err := cyclic.TopologicalSort(); !errors.Is(err, ErrCycle) {
+		t.Errorf("TopologicalSort() = %v %v, want ErrCycle", order, err)
+	}
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := FromAdjacency(map[string][]string{
+		"a": {"b"},
+		"b": {"a", "c"},
+		"c": {"d"},
+		"d": {"c", "e"},
+	})
+
+	got := g.StronglyConnectedComponents()
+	for _, component := range got {
+		slices.Sort(component)
+	}
+
+	// reverse topological order of the condensed graph
+	want := [][]string{{"e"}, {"c", "d"}, {"a", "b"}}
+	if !slices.EqualFunc(got, want, slices.Equal[[]string]) {
+		t.Errorf("StronglyConnectedComponents() = %v, want %v", got, want)
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		adjacency map[string][]string
+		cycle     []string
+	}{
+		{"triangle", map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a", "d"}}, []string{"a", "b", "c"}},
+		{"self loop", map[string][]string{"a": {"b"}, "b": {"b"}}, []string{"b"}},
+		{"dag", map[string][]string{"a": {"b", "c"}, "b": {"c"}}, nil},
+	}
+
+	for _, tt := range tests {
+		g := FromAdjacency(tt.adjacency)
+		cycle, ok := g.FindCycle()
+
+		if ok != (tt.cycle != nil) || !slices.Equal(cycle, tt.cycle) {
+			t.Errorf("%v: FindCycle() = %v %v, want %v", tt.name, cycle, ok, tt.cycle)
+		}
+		if g.HasCycle() != ok {
+			t.Errorf("%v: HasCycle() disagrees with FindCycle()", tt.name)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package graph
+
+// Orders nodes so every edge points forwards (Kahn's algorithm). Returns
+// ErrCycle if no such ordering exists.
+func (g *Graph[T]) TopologicalSort() ([]T, error) {
+	inDegree := make(map[T]int, len(g.nodes))
+	for _, node := range g.nodes {
+		for _, edge := range g.edges[node] {
+			inDegree[edge.To]++
+		}
+	}
+
+	queue := []T{}
+	for _, node := range g.nodes {
+		if inDegree[node] == 0 {
+			queue = append(queue, node)
+		}
+	}
+
+	order := make([]T, 0, len(g.nodes))
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		order = append(order, curr)
+
+		for _, edge := range g.edges[curr] {
+			inDegree[edge.To]--
+			if inDegree[edge.To] == 0 {
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	if len(order) != len(g.nodes) {
+		return nil, ErrCycle
+	}
+
+	return order, nil
+}
+
+// Groups nodes into strongly connected components (Tarjan's algorithm).
+// Components are returned in reverse topological order of the condensed graph.
+func (g *Graph[T]) StronglyConnectedComponents() [][]T {
+	t := &tarjan[T]{
+		graph:   g,
+		index:   map[T]int{},
+		low:     map[T]int{},
+		onStack: map[T]bool{},
+	}
+
+	for _, node := range g.nodes {
+		if _, visited := t.index[node]; !visited {
+			t.connect(node)
+		}
+	}
+
+	return t.components
+}
+
+// Reports whether any node can reach itself, including through a self loop
+func (g *Graph[T]) HasCycle() bool {
+	_, ok := g.FindCycle()
+	return ok
+}
+
+// Returns the nodes of one cycle in edge order, if any exists
+func (g *Graph[T]) FindCycle() ([]T, bool) {
+	const (
+		unvisited = iota
+		active
+		finished
+	)
+	state := map[T]int{}
+	parent := map[T]T{}
+
+	var visit func(T) ([]T, bool)
+	visit = func(node T) ([]T, bool) {
+		state[node] = active
+
+		for _, edge := range g.edges[node] {
+			switch state[edge.To] {
+			case active:
+				// walk back up the active path to close the cycle
+				cycle := []T{node}
+				for curr := node; curr != edge.To; {
+					curr = parent[curr]
+					cycle = append(cycle, curr)
+				}
+				for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				return cycle, true
+			case unvisited:
+				parent[edge.To] = node
+				if cycle, ok := visit(edge.To); ok {
+					return cycle, true
+				}
+			}
+		}
+
+		state[node] = finished
+		return nil, false
+	}
+
+	for _, node := range g.nodes {
+		if state[node] != unvisited {
+			continue
+		}
+		if cycle, ok := visit(node); ok {
+			return cycle, true
+		}
+	}
+
+	return nil, false
+}
+
+type tarjan[T comparable] struct {
+	graph      *Graph[T]
+	counter    int
+	index      map[T]int
+	low        map[T]int
+	onStack    map[T]bool
+	stack      []T
+	components [][]T
+}
+
+func (t *tarjan[T]) connect(node T) {
+	t.index[node] = t.counter
+	t.low[node] = t.counter
+	t.counter++
+	t.stack = append(t.stack, node)
+	t.onStack[node] = true
+
+	for _, edge := range t.graph.edges[node] {
+		if _, visited := t.index[edge.To]; !visited {
+			t.connect(edge.To)
+			t.low[node] = min(t.low[node], t.low[edge.To])
+		} else if t.onStack[edge.To] {
+			t.low[node] = min(t.low[node], t.index[edge.To])
+		}
+	}
+
+	if t.low[node] != t.index[node] {
+		return
+	}
+
+	component := []T{}
+	for {
+		top := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		t.onStack[top] = false
+		component = append(component, top)
+		if top == node {
+			break
+		}
+	}
+	t.components = append(t.components, component)
+}
@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"github.com/ryanpdenoux/advent-of-code/utils"
+)
+
+// Estimates the remaining cost from a node to the goal. Must be consistent
+// (never overestimate, even between neighbours) for AStar to stay optimal.
+type Heuristic[T comparable] func(node T) int
+
+// Heuristic that turns AStar into Dijkstra
+func Zero[T comparable](T) int {
+	return 0
+}
+
+// Computes the cheapest cost from start to every reachable node along with
+// the predecessor of each node on its cheapest path
+func (g *Graph[T]) Dijkstra(start T) (map[T]int, map[T]T) {
+	dist := map[T]int{}
+	prev := map[T]T{}
+
+	g.search(start, Zero[T], dist, prev, func(T) bool { return false })
+
+	return dist, prev
+}
+
+// Finds the cheapest path from start to goal, returning the path and its cost
+func (g *Graph[T]) ShortestPath(start, goal T) ([]T, int, bool) {
+	return g.AStar(start, goal, Zero[T])
+}
+
+// Finds the cheapest path from start to goal guided by heuristic
+func (g *Graph[T]) AStar(start, goal T, heuristic Heuristic[T]) ([]T, int, bool) {
+	dist := map[T]int{}
+	prev := map[T]T{}
+
+	found := g.search(start, heuristic, dist, prev, func(node T) bool {
+		return node == goal
+	})
+	if !found {
+		return nil, 0, false
+	}
+
+	return buildPath(prev, start, goal), dist[goal], true
+}
+
+func (g *Graph[T]) search(start T, heuristic Heuristic[T], dist map[T]int, prev map[T]T, done func(T) bool) bool {
+	if !g.Has(start) {
+		return false
+	}
+
+	closed := map[T]bool{}
+	queued := map[T]*utils.PQItem[searchItem[T]]{}
+	frontier := utils.NewPriorityQueue(func(a, b searchItem[T]) bool {
+		return a.priority < b.priority
+	})
+	dist[start] = 0
+	queued[start] = frontier.Push(searchItem[T]{start, heuristic(start)})
+
+	for frontier.Len() > 0 {
+		item, _ := frontier.Pop()
+		curr := item.node
+		closed[curr] = true
+
+		if done(curr) {
+			return true
+		}
+
+		for _, edge := range g.edges[curr] {
+			cost := dist[curr] + edge.Weight
+			if known, ok := dist[edge.To]; ok && known <= cost {
+				continue
+			}
+			if closed[edge.To] {
+				continue
+			}
+			dist[edge.To] = cost
+			prev[edge.To] = curr
+
+			next := searchItem[T]{edge.To, cost + heuristic(edge.To)}
+			if handle, ok := queued[edge.To]; ok && frontier.Contains(handle) {
+				frontier.Update(handle, next)
+			} else {
+				queued[edge.To] = frontier.Push(next)
+			}
+		}
+	}
+
+	return false
+}
+
+type searchItem[T comparable] struct {
+	node     T
+	priority int
+}
@@ -0,0 +1,110 @@
+package graph
+
+// Visits nodes in breadth first order along with their distance (in edges)
+// from start. Returning false from visit stops the traversal.
+func (g *Graph[T]) BFS(start T, visit func(node T, depth int) bool) {
+	g.bfs(start, visit)
+}
+
+// Visits nodes in depth first pre-order, following edges in insertion order.
+// Returning false from visit stops the traversal.
+func (g *Graph[T]) DFS(start T, visit func(node T) bool) {
+	if !g.Has(start) {
+		return
+	}
+
+	seen := map[T]bool{}
+	stack := []T{start}
+
+	for len(stack) > 0 {
+		curr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if seen[curr] {
+			continue
+		}
+		seen[curr] = true
+
+		if !visit(curr) {
+			return
+		}
+
+		// push in reverse so the first edge is explored first
+		edges := g.edges[curr]
+		for i := len(edges) - 1; i >= 0; i-- {
+			if !seen[edges[i].To] {
+				stack = append(stack, edges[i].To)
+			}
+		}
+	}
+}
+
+// Finds the path with the fewest edges from start to the first node that
+// satisfies goal
+func (g *Graph[T]) BFSPath(start T, goal func(node T) bool) ([]T, bool) {
+	var (
+		end   T
+		found bool
+	)
+
+	prev := g.bfs(start, func(node T, _ int) bool {
+		if goal(node) {
+			end = node
+			found = true
+			return false
+		}
+		return true
+	})
+
+	if !found {
+		return nil, false
+	}
+
+	return buildPath(prev, start, end), true
+}
+
+// Shared breadth first walk, returns the predecessor of every discovered node
+func (g *Graph[T]) bfs(start T, visit func(T, int) bool) map[T]T {
+	prev := map[T]T{}
+	if !g.Has(start) {
+		return prev
+	}
+
+	depths := map[T]int{start: 0}
+	queue := []T{start}
+
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+
+		if !visit(curr, depths[curr]) {
+			return prev
+		}
+
+		for _, edge := range g.edges[curr] {
+			if _, seen := depths[edge.To]; seen {
+				continue
+			}
+			depths[edge.To] = depths[curr] + 1
+			prev[edge.To] = curr
+			queue = append(queue, edge.To)
+		}
+	}
+
+	return prev
+}
+
+func buildPath[T comparable](prev map[T]T, start, end T) []T {
+	path := []T{end}
+
+	for curr := end; curr != start; {
+		curr = prev[curr]
+		path = append(path, curr)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Position in a Grid, rows count down from the top
+type Cell struct {
+	Row int
+	Col int
+}
+
+func (c Cell) String() string {
+	return fmt.Sprintf("(%d, %d)", c.Row, c.Col)
+}
+
+var (
+	orthogonal = []Cell{{-1, 0}, {0, -1}, {0, 1}, {1, 0}}
+	diagonal   = []Cell{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
+)
+
+// Rectangular block of characters read fully into memory
+type Grid [][]byte
+
+// Reads every line of r, a missing trailing newline is fine. Lines may have
+// different lengths, use InBounds before indexing directly.
+func ReadGrid(r io.Reader) (Grid, error) {
+	grid := Grid{}
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		row := make([]byte, len(line))
+		copy(row, line)
+		grid = append(grid, row)
+	}
+
+	// drop blank lines at the end of the input
+	for len(grid) > 0 && len(grid[len(grid)-1]) == 0 {
+		grid = grid[:len(grid)-1]
+	}
+
+	return grid, scanner.Err()
+}
+
+func (g Grid) String() string {
+	var sb strings.Builder
+	for _, row := range g {
+		sb.Write(row)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func (g Grid) Rows() int {
+	return len(g)
+}
+
+func (g Grid) InBounds(c Cell) bool {
+	return c.Row >= 0 && c.Row < len(g) && c.Col >= 0 && c.Col < len(g[c.Row])
+}
+
+func (g Grid) At(c Cell) (byte, bool) {
+	if !g.InBounds(c) {
+		return 0, false
+	}
+	return g[c.Row][c.Col], true
+}
+
+// In bounds cells sharing an edge with c
+func (g Grid) Neighbours4(c Cell) []Cell {
+	return g.offsets(c, orthogonal)
+}
+
+// In bounds cells sharing an edge or corner with c
+func (g Grid) Neighbours8(c Cell) []Cell {
+	return append(g.offsets(c, orthogonal), g.offsets(c, diagonal)...)
+}
+
+func (g Grid) offsets(c Cell, offsets []Cell) []Cell {
+	cells := []Cell{}
+
+	for _, offset := range offsets {
+		next := Cell{c.Row + offset.Row, c.Col + offset.Col}
+		if g.InBounds(next) {
+			cells = append(cells, next)
+		}
+	}
+
+	return cells
+}
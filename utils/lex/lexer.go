@@ -0,0 +1,190 @@
+package lex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type TokenType string
+
+// Token types produced regardless of configuration
+const (
+	ILLEGAL TokenType = "ILLEGAL"
+	EOF     TokenType = "EOF"
+	IDENT   TokenType = "IDENT"
+	INT     TokenType = "INT"
+)
+
+// Location of a token in the input, Line and Column are 1-based
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+type Token struct {
+	Type    TokenType
+	Literal string
+	Pos     Position
+}
+
+func (t Token) String() string {
+	return fmt.Sprintf("%s(%q)@%v", t.Type, t.Literal, t.Pos)
+}
+
+// Describes the vocabulary of a small grammar
+type Config struct {
+	// Fixed symbols such as ":" or "->", the longest match wins
+	Punctuation map[string]TokenType
+	// Reserved words, looked up whenever an identifier is read
+	Keywords map[string]TokenType
+	// Type of identifiers that are not keywords, defaults to IDENT
+	Ident TokenType
+	// Type of integer literals, defaults to INT
+	Int TokenType
+	// Type returned once the input is exhausted, defaults to EOF
+	EOF TokenType
+	// Lex a '-' directly followed by a digit as part of an integer literal
+	NegativeInts bool
+}
+
+// Tokenizes a string according to a Config. Whitespace, including newlines,
+// separates tokens and is otherwise skipped.
+type Lexer struct {
+	cfg    Config
+	puncts []string
+	input  string
+	pos    Position
+}
+
+func New(input string, cfg Config) *Lexer {
+	if cfg.Ident == "" {
+		cfg.Ident = IDENT
+	}
+	if cfg.Int == "" {
+		cfg.Int = INT
+	}
+	if cfg.EOF == "" {
+		cfg.EOF = EOF
+	}
+
+	l := &Lexer{
+		cfg:   cfg,
+		input: input,
+		pos:   Position{Line: 1, Column: 1},
+	}
+
+	for punct := range cfg.Punctuation {
+		l.puncts = append(l.puncts, punct)
+	}
+	// longest first so "->" is preferred over "-"
+	sort.Slice(l.puncts, func(i, j int) bool {
+		if len(l.puncts[i]) != len(l.puncts[j]) {
+			return len(l.puncts[i]) > len(l.puncts[j])
+		}
+		return l.puncts[i] < l.puncts[j]
+	})
+
+	return l
+}
+
+func (l *Lexer) NextToken() Token {
+	l.skipWhitespace()
+	start := l.pos
+
+	if l.pos.Offset >= len(l.input) {
+		return Token{Type: l.cfg.EOF, Pos: start}
+	}
+
+	ch := l.input[l.pos.Offset]
+	switch {
+	case l.cfg.NegativeInts && ch == '-' && isDigit(l.peekChar()):
+		l.advance(1)
+		l.readWhile(isDigit)
+		return l.token(l.cfg.Int, start)
+	case isDigit(ch):
+		l.readWhile(isDigit)
+		return l.token(l.cfg.Int, start)
+	case isLetter(ch):
+		l.readWhile(func(c byte) bool { return isLetter(c) || isDigit(c) })
+		tok := l.token(l.cfg.Ident, start)
+		if keyword, ok := l.cfg.Keywords[tok.Literal]; ok {
+			tok.Type = keyword
+		}
+		return tok
+	}
+
+	for _, punct := range l.puncts {
+		if strings.HasPrefix(l.input[l.pos.Offset:], punct) {
+			l.advance(len(punct))
+			return l.token(l.cfg.Punctuation[punct], start)
+		}
+	}
+
+	l.advance(1)
+	return l.token(ILLEGAL, start)
+}
+
+// Lexes the remaining input, including the final EOF token
+func (l *Lexer) All() []Token {
+	tokens := []Token{}
+
+	for {
+		tok := l.NextToken()
+		tokens = append(tokens, tok)
+		if tok.Type == l.cfg.EOF {
+			return tokens
+		}
+	}
+}
+
+func (l *Lexer) token(tokenType TokenType, start Position) Token {
+	return Token{
+		Type:    tokenType,
+		Literal: l.input[start.Offset:l.pos.Offset],
+		Pos:     start,
+	}
+}
+
+func (l *Lexer) peekChar() byte {
+	if l.pos.Offset+1 >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos.Offset+1]
+}
+
+func (l *Lexer) advance(n int) {
+	for i := 0; i < n && l.pos.Offset < len(l.input); i++ {
+		if l.input[l.pos.Offset] == '\n' {
+			l.pos.Line++
+			l.pos.Column = 0
+		}
+		l.pos.Offset++
+		l.pos.Column++
+	}
+}
+
+func (l *Lexer) readWhile(match func(byte) bool) {
+	for l.pos.Offset < len(l.input) && match(l.input[l.pos.Offset]) {
+		l.advance(1)
+	}
+}
+
+func (l *Lexer) skipWhitespace() {
+	l.readWhile(func(c byte) bool {
+		return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+	})
+}
+
+func isLetter(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+}
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
@@ -0,0 +1,131 @@
+package lex
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const (
+	ARROW TokenType = "ARROW"
+	MINUS TokenType = "MINUS"
+	COLON TokenType = "COLON"
+	LET   TokenType = "LET"
+)
+
+var testSyntax = Config{
+	Punctuation: map[string]TokenType{
+		"->": ARROW,
+		"-":  MINUS,
+		":":  COLON,
+	},
+	Keywords: map[string]TokenType{
+		"let": LET,
+	},
+}
+
+type lexed struct {
+	Type    TokenType
+	Literal string
+	Pos     string
+}
+
+func lexAll(input string, cfg Config) []lexed {
+	got := []lexed{}
+	for _, tok := range New(input, cfg).All() {
+		got = append(got, lexed{tok.Type, tok.Literal, tok.Pos.String()})
+	}
+	return got
+}
+
+func TestLexer(t *testing.T) {
+	negative := testSyntax
+	negative.NegativeInts = true
+
+	tests := []struct {
+		name  string
+		input string
+		cfg   Config
+		want  []lexed
+	}{
+		{
+			"positions across newlines", "let a:\n  b12\r\n\n7", testSyntax,
+			[]lexed{
+				{LET, "let", "1:1"}, {IDENT, "a", "1:5"}, {COLON, ":", "1:6"},
+				{IDENT, "b12", "2:3"}, {INT, "7", "4:1"}, {EOF, "", "4:2"},
+			},
+		},
+		{
+			"longest punctuation", "a->b - ->-", testSyntax,
+			[]lexed{
+				{IDENT, "a", "1:1"}, {ARROW, "->", "1:2"}, {IDENT, "b", "1:4"},
+				{MINUS, "-", "1:6"}, {ARROW, "->", "1:8"}, {MINUS, "-", "1:10"}, {EOF, "", "1:11"},
+			},
+		},
+		{
+			"minus without NegativeInts", "b-3", testSyntax,
+			[]lexed{{IDENT, "b", "1:1"}, {MINUS, "-", "1:2"}, {INT, "3", "1:3"}, {EOF, "", "1:4"}},
+		},
+		{
+			// the '-' belongs to the number even straight after an identifier
+			"NegativeInts", "b-3 - 4 -x", negative,
+			[]lexed{
+				{IDENT, "b", "1:1"}, {INT, "-3", "1:2"}, {MINUS, "-", "1:5"}, {INT, "4", "1:7"},
+				{MINUS, "-", "1:9"}, {IDENT, "x", "1:10"}, {EOF, "", "1:11"},
+			},
+		},
+		{
+			"illegal", "a ? b", testSyntax,
+			[]lexed{{IDENT, "a", "1:1"}, {ILLEGAL, "?", "1:3"}, {IDENT, "b", "1:5"}, {EOF, "", "1:6"}},
+		},
+		{
+			"custom types", "Game 1", Config{Keywords: map[string]TokenType{"Game": LET}, Ident: "WORD", Int: "NUM", EOF: "END"},
+			[]lexed{{LET, "Game", "1:1"}, {"NUM", "1", "1:6"}, {"END", "", "1:7"}},
+		},
+		{"empty", "  \n", testSyntax, []lexed{{EOF, "", "2:1"}}},
+	}
+
+	for _, tt := range tests {
+		if got := lexAll(tt.input, tt.cfg); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: lexed %q = %v, want %v", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	s := NewStream(New("let x -> 5", testSyntax))
+
+	if !s.CurrIs(LET) || !s.PeekIs(IDENT) {
+		t.Fatalf("stream starts at %v %v, want LET IDENT", s.Curr(), s.Peek())
+	}
+
+	tok, err := s.Expect(LET)
+	if err != nil || tok.Literal != "let" {
+		t.Errorf("Expect(LET) = %v %v", tok, err)
+	}
+
+	// a failed Expect leaves the stream where it was
+	_, err = s.Expect(INT, COLON)
+	var unexpected *UnexpectedError
+	if !errors.As(err, &unexpected) || unexpected.Got.Type != IDENT {
+		t.Errorf("Expect(INT, COLON) error = %v, want UnexpectedError at the IDENT", err)
+	}
+	if want := `1:5: expected INT or COLON, got IDENT "x"`; err.Error() != want {
+		t.Errorf("Expect(INT, COLON) error = %q, want %q", err, want)
+	}
+	if !s.CurrIs(IDENT) {
+		t.Errorf("Curr() = %v after a failed Expect, want the IDENT", s.Curr())
+	}
+
+	if s.ExpectPeek(INT) {
+		t.Errorf("ExpectPeek(INT) advanced onto %v", s.Curr())
+	}
+	if !s.ExpectPeek(ARROW) || !s.CurrIs(ARROW) {
+		t.Errorf("ExpectPeek(ARROW) left the stream at %v", s.Curr())
+	}
+
+	s.Next()
+	if tok := s.Next(); tok.Type != EOF || !s.PeekIs(EOF) {
+		t.Errorf("Next() past the input = %v, peek %v, want EOF twice", tok, s.Peek())
+	}
+}
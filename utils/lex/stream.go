@@ -0,0 +1,86 @@
+package lex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Returned by Expect when the current token is not one of the wanted types
+type UnexpectedError struct {
+	Want []TokenType
+	Got  Token
+}
+
+func (e *UnexpectedError) Error() string {
+	want := make([]string, len(e.Want))
+	for i, t := range e.Want {
+		want[i] = string(t)
+	}
+	return fmt.Sprintf("%v: expected %s, got %s %q", e.Got.Pos, strings.Join(want, " or "), e.Got.Type, e.Got.Literal)
+}
+
+// Two token window over a Lexer for writing recursive descent / Pratt parsers
+type Stream struct {
+	lexer *Lexer
+	curr  Token
+	peek  Token
+}
+
+func NewStream(lexer *Lexer) *Stream {
+	s := &Stream{lexer: lexer}
+	s.Next()
+	s.Next()
+	return s
+}
+
+func (s *Stream) Curr() Token {
+	return s.curr
+}
+
+func (s *Stream) Peek() Token {
+	return s.peek
+}
+
+// Advances by one token and returns the new current token
+func (s *Stream) Next() Token {
+	s.curr = s.peek
+	s.peek = s.lexer.NextToken()
+	return s.curr
+}
+
+func (s *Stream) CurrIs(types ...TokenType) bool {
+	return tokenIs(s.curr, types)
+}
+
+func (s *Stream) PeekIs(types ...TokenType) bool {
+	return tokenIs(s.peek, types)
+}
+
+// Advances only if the peeked token has the given type
+func (s *Stream) ExpectPeek(tokenType TokenType) bool {
+	if !s.PeekIs(tokenType) {
+		return false
+	}
+	s.Next()
+	return true
+}
+
+// Consumes the current token if it matches one of types, otherwise returns
+// an *UnexpectedError and leaves the stream untouched
+func (s *Stream) Expect(types ...TokenType) (Token, error) {
+	if !s.CurrIs(types...) {
+		return s.curr, &UnexpectedError{Want: types, Got: s.curr}
+	}
+	tok := s.curr
+	s.Next()
+	return tok, nil
+}
+
+func tokenIs(tok Token, types []TokenType) bool {
+	for _, t := range types {
+		if tok.Type == t {
+			return true
+		}
+	}
+	return false
+}
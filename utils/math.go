@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"math"
+	"math/big"
+)
+
+// Floor of the square root of n computed with integers only (Newton's method)
+func ISqrt(n int) int {
+	if n < 0 {
+		panic("square root of negative number")
+	}
+	if n < 2 {
+		return n
+	}
+
+	// (n + 1) / 2 without the sum, which wraps for math.MaxInt
+	x := n
+	y := n/2 + n%2
+	for y < x {
+		x = y
+		y = (x + n/x) / 2
+	}
+
+	return x
+}
+
+func GCD(a, b int) int {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Least common multiple of nums, or false if it does not fit in an int
+func LCM(nums ...int) (int, bool) {
+	if len(nums) == 0 {
+		return 0, true
+	}
+
+	lcm := nums[0]
+	for _, num := range nums[1:] {
+		if lcm == 0 || num == 0 {
+			return 0, true
+		}
+		step := lcm / GCD(lcm, num)
+		if step > math.MaxInt/num {
+			return 0, false
+		}
+		lcm = step * num
+	}
+	return lcm, true
+}
+
+// Solves x ≡ residues[i] (mod moduli[i]) for every i. Moduli do not need to
+// be coprime. Returns the smallest non-negative x and the combined modulus,
+// or false if the congruences contradict each other or the combined modulus
+// does not fit in an int.
+func CRT(residues, moduli []int) (int, int, bool) {
+	x, m := big.NewInt(0), big.NewInt(1)
+
+	for i := range residues {
+		a, n := big.NewInt(int64(residues[i])), big.NewInt(int64(moduli[i]))
+
+		// x + m*k ≡ a (mod n)  =>  m*k ≡ a-x (mod n)
+		g, p := new(big.Int), new(big.Int)
+		g.GCD(p, nil, m, n)
+		diff := new(big.Int).Sub(a, x)
+		if new(big.Int).Mod(diff, g).Sign() != 0 {
+			return 0, 0, false
+		}
+
+		step := new(big.Int).Div(n, g)
+		k := new(big.Int).Div(diff, g)
+		k.Mul(k, p).Mod(k, step)
+
+		x.Add(x, new(big.Int).Mul(m, k))
+		m.Mul(m, step)
+		x.Mod(x, m)
+	}
+
+	if !x.IsInt64() || !m.IsInt64() || m.Int64() > math.MaxInt {
+		return 0, 0, false
+	}
+	return int(x.Int64()), int(m.Int64()), true
+}
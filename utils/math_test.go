@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCRT(t *testing.T) {
+	tests := []struct {
+		name             string
+		residues, moduli []int
+		x, m             int
+		ok               bool
+	}{
+		{"coprime", []int{2, 3, 2}, []int{3, 5, 7}, 23, 105, true},
+		{"shared factor", []int{1, 3}, []int{4, 6}, 9, 12, true},
+		{"contradiction", []int{0, 1}, []int{4, 6}, 0, 0, false},
+		{"residue above modulus", []int{7}, []int{5}, 2, 5, true},
+		{"modulus overflows int", []int{0, 0, 0}, []int{math.MaxInt32, math.MaxInt32 - 1, math.MaxInt32 - 2}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		x, m, ok := CRT(tt.residues, tt.moduli)
+		if x != tt.x || m != tt.m || ok != tt.ok {
+			t.Errorf("%v: CRT(%v, %v) = %v %v %v, want %v %v %v", tt.name, tt.residues, tt.moduli, x, m, ok, tt.x, tt.m, tt.ok)
+		}
+	}
+}
+
+func TestLCM(t *testing.T) {
+	tests := []struct {
+		nums []int
+		want int
+		ok   bool
+	}{
+		{[]int{4, 6}, 12, true},
+		{[]int{2, 3, 5, 7}, 210, true},
+		{[]int{5}, 5, true},
+		{[]int{3, 0}, 0, true},
+		{nil, 0, true},
+		// the shared factor keeps it in range
+		{[]int{math.MaxInt32 * 2, math.MaxInt32 * 4}, math.MaxInt32 * 4, true},
+		{[]int{math.MaxInt32, math.MaxInt32 - 1, math.MaxInt32 - 2}, 0, false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := LCM(tt.nums...); got != tt.want || ok != tt.ok {
+			t.Errorf("LCM(%v) = %v %v, want %v %v", tt.nums, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestISqrt(t *testing.T) {
+	tests := []struct {
+		n, want int
+	}{
+		{0, 0},
+		{1, 1},
+		{3, 1},
+		{4, 2},
+		{99, 9},
+		{100, 10},
+		{math.MaxInt, 3037000499},
+	}
+
+	for _, tt := range tests {
+		if got := ISqrt(tt.n); got != tt.want {
+			t.Errorf("ISqrt(%v) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Caches the results of a pure function. Use a struct for K to memoize on
+// composite keys.
+func Memoize[K comparable, V any](fn func(K) V) func(K) V {
+	cache := make(map[K]V)
+
+	return func(key K) V {
+		if value, ok := cache[key]; ok {
+			return value
+		}
+		value := fn(key)
+		cache[key] = value
+		return value
+	}
+}
+
+// Memoizes a two argument function by pairing its arguments into one key
+func Memoize2[A, B comparable, V any](fn func(A, B) V) func(A, B) V {
+	type pair struct {
+		a A
+		b B
+	}
+	memoized := Memoize(func(key pair) V {
+		return fn(key.a, key.b)
+	})
+
+	return func(a A, b B) V {
+		return memoized(pair{a, b})
+	}
+}
+
+// Memoizes a recursive function, fn receives the cached version of itself
+// so every recursive call goes through the cache
+func MemoizeRecursive[K comparable, V any](fn func(self func(K) V, key K) V) func(K) V {
+	var memoized func(K) V
+	cache := make(map[K]V)
+
+	memoized = func(key K) V {
+		if value, ok := cache[key]; ok {
+			return value
+		}
+		value := fn(memoized, key)
+		cache[key] = value
+		return value
+	}
+
+	return memoized
+}
+
+// Builds a map key out of values that are not comparable (e.g. slices)
+func CompositeKey(parts ...any) string {
+	strs := make([]string, len(parts))
+	for i, part := range parts {
+		strs[i] = fmt.Sprintf("%v", part)
+	}
+	return strings.Join(strs, "\x00")
+}
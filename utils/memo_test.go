@@ -0,0 +1,72 @@
+package utils
+
+import "testing"
+
+func TestMemoize(t *testing.T) {
+	calls := 0
+	square := Memoize(func(n int) int {
+		calls++
+		return n * n
+	})
+
+	for _, n := range []int{3, 4, 3, 3, 4} {
+		if got := square(n); got != n*n {
+			t.Errorf("square(%v) = %v, want %v", n, got, n*n)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("square ran %d times, want 2", calls)
+	}
+}
+
+func TestMemoize2(t *testing.T) {
+	calls := 0
+	join := Memoize2(func(a string, b int) string {
+		calls++
+		return CompositeKey(a, b)
+	})
+
+	join("a", 1)
+	join("a", 2)
+	join("a", 1)
+	if calls != 2 {
+		t.Errorf("join ran %d times, want 2", calls)
+	}
+}
+
+func TestMemoizeRecursive(t *testing.T) {
+	calls := 0
+	fib := MemoizeRecursive(func(self func(int) int, n int) int {
+		calls++
+		if n < 2 {
+			return n
+		}
+		return self(n-1) + self(n-2)
+	})
+
+	if got := fib(90); got != 2880067194370816120 {
+		t.Errorf("fib(90) = %v, want 2880067194370816120", got)
+	}
+	// every n from 0 to 90 once
+	if calls != 91 {
+		t.Errorf("fib ran %d times, want 91", calls)
+	}
+}
+
+func TestCompositeKey(t *testing.T) {
+	tests := []struct {
+		a, b []any
+		same bool
+	}{
+		{[]any{[]int{1, 2}, "x"}, []any{[]int{1, 2}, "x"}, true},
+		{[]any{[]int{1, 2}, "x"}, []any{[]int{1}, "x"}, false},
+		{[]any{"a b", "c"}, []any{"a", "b c"}, false},
+		{[]any{1, 2}, []any{2, 1}, false},
+	}
+
+	for _, tt := range tests {
+		if same := CompositeKey(tt.a...) == CompositeKey(tt.b...); same != tt.same {
+			t.Errorf("CompositeKey(%v) == CompositeKey(%v) is %v, want %v", tt.a, tt.b, same, tt.same)
+		}
+	}
+}
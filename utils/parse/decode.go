@@ -0,0 +1,156 @@
+package parse
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Returned when a line does not fit the pattern. Column is 1-based and
+// points at the first character that could not be matched.
+type MismatchError struct {
+	Pattern  string
+	Line     string
+	Column   int
+	Expected string
+	After    string
+}
+
+func (e *MismatchError) Error() string {
+	msg := fmt.Sprintf("line %q does not match pattern %q: expected %s at column %d", e.Line, e.Pattern, e.Expected, e.Column)
+	if e.After != "" {
+		msg += fmt.Sprintf(" (after %s)", e.After)
+	}
+	return msg
+}
+
+// Returned when captured text cannot be stored in the destination field
+type FieldError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field {%s} with value %q: %v", e.Field, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Decodes line into the struct pointed to by v. Pattern fields are matched
+// to struct fields by a `parse:"name"` tag or, failing that, by a case
+// insensitive field name. Supported field types are string, any integer
+// type, and slices of those.
+func (p *Pattern) Decode(line string, v any) error {
+	dest := reflect.ValueOf(v)
+	if dest.Kind() != reflect.Pointer || dest.IsNil() || dest.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode destination must be a non-nil struct pointer, got %T", v)
+	}
+	dest = dest.Elem()
+
+	captures, err := p.Match(line)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range p.Fields() {
+		field, ok := lookupField(dest, name)
+		if !ok {
+			return fmt.Errorf("no field in %v for {%s}", dest.Type(), name)
+		}
+		if err := setField(field, captures[name]); err != nil {
+			return &FieldError{Field: name, Value: captures[name], Err: err}
+		}
+	}
+
+	return nil
+}
+
+// Decodes every non-empty line of r into a T, errors mention the line number
+func Lines[T any](r io.Reader, p *Pattern) ([]T, error) {
+	items := []T{}
+	scanner := bufio.NewScanner(r)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		var item T
+		if err := p.Decode(line, &item); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, scanner.Err()
+}
+
+func lookupField(dest reflect.Value, name string) (reflect.Value, bool) {
+	t := dest.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		if tag, ok := t.Field(i).Tag.Lookup("parse"); ok && tag == name {
+			return dest.Field(i), true
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if _, tagged := f.Tag.Lookup("parse"); !tagged && f.IsExported() && strings.EqualFold(f.Name, name) {
+			return dest.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+func setField(field reflect.Value, raw string) error {
+	if !field.CanSet() {
+		return errors.New("destination field is not settable")
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(num)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		num, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(num)
+	case reflect.Slice:
+		items := splitList(raw)
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setField(slice.Index(i), item); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		field.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field type %v", field.Type())
+	}
+
+	return nil
+}
+
+func splitList(raw string) []string {
+	return strings.FieldsFunc(raw, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+}
@@ -0,0 +1,175 @@
+package parse
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type node struct {
+	Name  string
+	Left  string
+	Right string
+}
+
+type card struct {
+	ID   int   `parse:"id"`
+	Win  []int `parse:"win"`
+	Have []int
+}
+
+func TestDecodeNode(t *testing.T) {
+	p := MustCompile("{name} = ({left}, {right})")
+
+	got := node{}
+	if err := p.Decode("AAA = (BBB, CCC)", &got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if want := (node{"AAA", "BBB", "CCC"}); got != want {
+		t.Errorf("Decode() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCard(t *testing.T) {
+	p := MustCompile("Card {id:int}: {win:[]int} | {have:[]int}")
+
+	tests := []struct {
+		line string
+		want card
+	}{
+		{"Card 1: 41 48 83 | 83 86  6", card{1, []int{41, 48, 83}, []int{83, 86, 6}}},
+		{"Card   12:  1 21 |  9", card{12, []int{1, 21}, []int{9}}},
+		{"Card 3: 4,5, 6 | 7 ,8", card{3, []int{4, 5, 6}, []int{7, 8}}},
+		{"Card 4:  | -1", card{4, []int{}, []int{-1}}},
+	}
+
+	for _, tt := range tests {
+		got := card{}
+		if err := p.Decode(tt.line, &got); err != nil {
+			t.Errorf("Decode(%q) error = %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Decode(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestBraceEscapes(t *testing.T) {
+	p := MustCompile("{{{key:word}}} = {value:int}")
+
+	got, err := p.Match("{x} = 5")
+	if err != nil {
+		t.Fatalf("Match() error = %v", err)
+	}
+	if got["key"] != "x" || got["value"] != "5" {
+		t.Errorf("Match() = %v, want key x and value 5", got)
+	}
+	if want := []string{"key", "value"}; !reflect.DeepEqual(p.Fields(), want) {
+		t.Errorf("Fields() = %v, want %v", p.Fields(), want)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		pattern string
+		reason  string
+	}{
+		{"{a}{b}", "need a literal between them"},
+		{"{a} {a}", "duplicate field"},
+		{"{a", "unterminated field"},
+		{"a}", "unexpected '}'"},
+		{"{a:float}", "unknown kind"},
+		{"{}", "field without a name"},
+		{"{a-b}", "invalid field name"},
+	}
+
+	for _, tt := range tests {
+		_, err := Compile(tt.pattern)
+		if err == nil || !strings.Contains(err.Error(), tt.reason) {
+			t.Errorf("Compile(%q) error = %v, want %q", tt.pattern, err, tt.reason)
+		}
+	}
+}
+
+func TestFieldLookup(t *testing.T) {
+	type tagged struct {
+		Name  string `parse:"other"`
+		Value string `parse:"name"`
+	}
+	p := MustCompile("{name}-{other}")
+
+	got := tagged{}
+	if err := p.Decode("a-b", &got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	// tags win over the field with the matching name
+	if got.Value != "a" || got.Name != "b" {
+		t.Errorf("Decode() = %+v, want Value a and Name b", got)
+	}
+
+	var missing struct{ Name string }
+	if err := p.Decode("a-b", &missing); err == nil {
+		t.Errorf("Decode() without a field for {other} succeeded")
+	}
+}
+
+func TestMismatchColumn(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		line     string
+		column   int
+		expected string
+	}{
+		// inside a field: the int stops at the 'x' and ':' is missing
+		{"Card {id:int}: {win:[]int}", "Card 1x: 4", 7, `": "`},
+		{"Card {id:int}: {win:[]int} | {have:[]int}", "Card 1: 41 x8 | 83", 12, `" | "`},
+		{"{name} = ({left}, {right})", "AAA = BBB, CCC)", 7, `" = ("`},
+		{"{a:int} {b:int}", "1 2 3", 4, "end of line"},
+		{"{name} = ({left}, {right})", "AAA", 4, `" = ("`},
+		// free text would swallow the rest, the ';' is where it should stop
+		{"{name} = ({left}, {right})", "AAA = (BBB; CCC)", 11, `", "`},
+		{"{name} = ({left}, {right})", "AAA = (BBB, CCC", 16, `")"`},
+	}
+
+	for _, tt := range tests {
+		_, err := MustCompile(tt.pattern).Match(tt.line)
+
+		var mismatch *MismatchError
+		if !errors.As(err, &mismatch) {
+			t.Errorf("Match(%q) error = %v, want MismatchError", tt.line, err)
+			continue
+		}
+		if mismatch.Column != tt.column || mismatch.Expected != tt.expected {
+			t.Errorf("Match(%q) = column %d expected %s, want column %d expected %s",
+				tt.line, mismatch.Column, mismatch.Expected, tt.column, tt.expected)
+		}
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	var dest struct{ Small int8 }
+	err := MustCompile("{small:int}").Decode("300", &dest)
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "small" {
+		t.Errorf("Decode() error = %v, want FieldError for {small}", err)
+	}
+}
+
+func TestLines(t *testing.T) {
+	input := "AAA = (BBB, CCC)\n\nBBB = (DDD, EEE)\n"
+	got, err := Lines[node](strings.NewReader(input), MustCompile("{name} = ({left}, {right})"))
+	if err != nil {
+		t.Fatalf("Lines() error = %v", err)
+	}
+	if len(got) != 2 || got[1].Name != "BBB" {
+		t.Errorf("Lines() = %+v", got)
+	}
+
+	_, err = Lines[node](strings.NewReader("AAA = (B, C)\noops"), MustCompile("{name} = ({left}, {right})"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("Lines() error = %v, want it to name line 2", err)
+	}
+}
@@ -0,0 +1,281 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Shape of the text a field is allowed to capture
+type kind string
+
+const (
+	kindString  kind = ""
+	kindWord    kind = "word"
+	kindInt     kind = "int"
+	kindInts    kind = "[]int"
+	kindStrings kind = "[]string"
+)
+
+var kindExpr = map[kind]string{
+	kindString:  `(.*?)`,
+	kindWord:    `(\w+)`,
+	kindInt:     `([-+]?\d+)`,
+	kindInts:    `([-+\d\s,]*?)`,
+	kindStrings: `(.*?)`,
+}
+
+// A literal run of text or a named field of a pattern
+type segment struct {
+	literal string
+	name    string
+	kind    kind
+}
+
+func (s segment) isField() bool {
+	return s.name != ""
+}
+
+func (s segment) String() string {
+	if !s.isField() {
+		return fmt.Sprintf("%q", s.literal)
+	}
+	if s.kind == kindString {
+		return fmt.Sprintf("{%s}", s.name)
+	}
+	return fmt.Sprintf("{%s:%s}", s.name, s.kind)
+}
+
+// Compiled line format such as "{name} = ({left}, {right})" or
+// "Card {id:int}: {win:[]int} | {have:[]int}". Fields are written as
+// {name} or {name:kind} where kind is one of word, int, []int or []string.
+// Any run of whitespace in the literal text matches one or more whitespace
+// characters in the input, and "{{" / "}}" match literal braces.
+type Pattern struct {
+	source   string
+	segments []segment
+	re       *regexp.Regexp
+}
+
+func Compile(pattern string) (*Pattern, error) {
+	segments, err := splitPattern(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	p := &Pattern{source: pattern, segments: segments}
+	p.re = regexp.MustCompile("^" + p.expr(len(segments)) + "$")
+	return p, nil
+}
+
+func MustCompile(pattern string) *Pattern {
+	p, err := Compile(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// Names of the fields in the order they appear in the pattern
+func (p *Pattern) Fields() []string {
+	names := []string{}
+	for _, seg := range p.segments {
+		if seg.isField() {
+			names = append(names, seg.name)
+		}
+	}
+	return names
+}
+
+// Returns the raw text captured by every field
+func (p *Pattern) Match(line string) (map[string]string, error) {
+	groups := p.re.FindStringSubmatch(line)
+	if groups == nil {
+		return nil, p.mismatch(line)
+	}
+
+	captures := make(map[string]string)
+	i := 1
+	for _, seg := range p.segments {
+		if seg.isField() {
+			captures[seg.name] = strings.TrimSpace(groups[i])
+			i++
+		}
+	}
+
+	return captures, nil
+}
+
+// Builds the regular expression for the first n segments
+func (p *Pattern) expr(n int) string {
+	var sb strings.Builder
+
+	for _, seg := range p.segments[:n] {
+		if seg.isField() {
+			sb.WriteString(kindExpr[seg.kind])
+			continue
+		}
+		sb.WriteString(literalExpr(seg.literal))
+	}
+
+	return sb.String()
+}
+
+// Finds how far into the pattern the line still matches, so the error can
+// point at the segment that broke
+func (p *Pattern) mismatch(line string) error {
+	err := &MismatchError{Pattern: p.source, Line: line}
+	matched := 0
+
+	for n := 1; n <= len(p.segments); n++ {
+		prefix := regexp.MustCompile("^" + p.expr(n))
+		prefix.Longest()
+		loc := prefix.FindStringIndex(line)
+		if loc == nil {
+			err.Expected = p.segments[n-1].String()
+			if n > 1 {
+				err.After = p.segments[n-2].String()
+			}
+			err.Column = p.partialEnd(line, n, matched) + 1
+			return err
+		}
+		matched = loc[1]
+	}
+
+	err.Expected = "end of line"
+	err.Column = matched + 1
+	return err
+}
+
+// Where a literal segment n stops matching: the longest match of the
+// segments before it plus part of the literal. Without this a free text
+// field before the literal would swallow the line and hide the break
+func (p *Pattern) partialEnd(line string, n, matched int) int {
+	seg := p.segments[n-1]
+	if seg.isField() {
+		return matched
+	}
+
+	runes := []rune(seg.literal)
+	for k := len(runes) - 1; k > 0; k-- {
+		prefix := regexp.MustCompile("^" + p.expr(n-1) + literalExpr(string(runes[:k])))
+		prefix.Longest()
+		if loc := prefix.FindStringIndex(line); loc != nil {
+			return loc[1]
+		}
+	}
+
+	// none of the literal is there, so free text before it ran to the end
+	// of the line. Point at the first character it captured that is not
+	// part of a word, as in the ';' of "AAA = (BBB; CCC)"
+	if n > 1 && p.segments[n-2].isField() && p.segments[n-2].kind == kindString {
+		prefix := regexp.MustCompile("^" + p.expr(n-1))
+		prefix.Longest()
+		if loc := prefix.FindStringSubmatchIndex(line); loc != nil {
+			start := loc[len(loc)-2]
+			for i, r := range line[start:] {
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) {
+					return start + i
+				}
+			}
+		}
+	}
+
+	return matched
+}
+
+func literalExpr(literal string) string {
+	var sb strings.Builder
+	inSpace := false
+
+	for _, r := range literal {
+		if unicode.IsSpace(r) {
+			if !inSpace {
+				sb.WriteString(`\s+`)
+			}
+			inSpace = true
+			continue
+		}
+		inSpace = false
+		sb.WriteString(regexp.QuoteMeta(string(r)))
+	}
+
+	return sb.String()
+}
+
+func splitPattern(pattern string) ([]segment, error) {
+	segments := []segment{}
+	seen := map[string]bool{}
+	var literal strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+
+		switch {
+		case ch == '{' && i+1 < len(pattern) && pattern[i+1] == '{':
+			literal.WriteByte('{')
+			i++
+		case ch == '}' && i+1 < len(pattern) && pattern[i+1] == '}':
+			literal.WriteByte('}')
+			i++
+		case ch == '}':
+			return nil, fmt.Errorf("unexpected '}' at column %d", i+1)
+		case ch == '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated field at column %d", i+1)
+			}
+			field, err := parseField(pattern[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("column %d: %w", i+1, err)
+			}
+			if seen[field.name] {
+				return nil, fmt.Errorf("duplicate field %q", field.name)
+			}
+			if len(segments) > 0 && segments[len(segments)-1].isField() && literal.Len() == 0 {
+				return nil, fmt.Errorf("fields %v and %v need a literal between them", segments[len(segments)-1], field)
+			}
+			seen[field.name] = true
+
+			if literal.Len() > 0 {
+				segments = append(segments, segment{literal: literal.String()})
+				literal.Reset()
+			}
+			segments = append(segments, field)
+			i += end
+		default:
+			literal.WriteByte(ch)
+		}
+	}
+
+	if literal.Len() > 0 {
+		segments = append(segments, segment{literal: literal.String()})
+	}
+
+	return segments, nil
+}
+
+func parseField(spec string) (segment, error) {
+	name, k, _ := strings.Cut(spec, ":")
+	name = strings.TrimSpace(name)
+	field := segment{name: name, kind: kind(strings.TrimSpace(k))}
+
+	if name == "" {
+		return field, fmt.Errorf("field without a name")
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return field, fmt.Errorf("invalid field name %q", name)
+		}
+	}
+	if _, ok := kindExpr[field.kind]; !ok {
+		return field, fmt.Errorf("unknown kind %q for field %q", k, name)
+	}
+
+	return field, nil
+}
@@ -0,0 +1,201 @@
+package poly
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var ErrDuplicateX = errors.New("sample x values must be distinct")
+
+// Polynomial with exact rational coefficients, coeffs[i] multiplies x^i
+type Polynomial struct {
+	coeffs []*big.Rat
+}
+
+func New(coeffs ...*big.Rat) Polynomial {
+	p := Polynomial{coeffs: make([]*big.Rat, len(coeffs))}
+	for i, c := range coeffs {
+		p.coeffs[i] = new(big.Rat).Set(c)
+	}
+	p.trim()
+	return p
+}
+
+// Fits the lowest degree polynomial through ys sampled at x = 0, 1, 2, ...
+// using Newton forward differences
+func FitSequence(ys []int) Polynomial {
+	row := make([]*big.Rat, len(ys))
+	for i, y := range ys {
+		row[i] = new(big.Rat).SetInt64(int64(y))
+	}
+
+	result := Polynomial{}
+	// C(x, j) as a polynomial, starts at C(x, 0) = 1
+	basis := New(big.NewRat(1, 1))
+
+	for j := 0; len(row) > 0; j++ {
+		if allZero(row) {
+			break
+		}
+		result = result.Add(basis.Scale(row[0]))
+
+		// C(x, j+1) = C(x, j) * (x - j) / (j + 1)
+		basis = basis.Mul(New(big.NewRat(int64(-j), 1), big.NewRat(1, 1))).Scale(big.NewRat(1, int64(j+1)))
+
+		for i := 0; i < len(row)-1; i++ {
+			row[i] = new(big.Rat).Sub(row[i+1], row[i])
+		}
+		row = row[:len(row)-1]
+	}
+
+	return result
+}
+
+// Fits the polynomial of degree < len(xs) through the points (xs[i], ys[i])
+func Lagrange(xs, ys []int) (Polynomial, error) {
+	if len(xs) != len(ys) {
+		return Polynomial{}, fmt.Errorf("got %d x values and %d y values", len(xs), len(ys))
+	}
+
+	result := Polynomial{}
+	for i := range xs {
+		term := New(new(big.Rat).SetInt64(int64(ys[i])))
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			denom := int64(xs[i] - xs[j])
+			if denom == 0 {
+				return Polynomial{}, ErrDuplicateX
+			}
+			// (x - xs[j]) / (xs[i] - xs[j])
+			factor := New(big.NewRat(int64(-xs[j]), denom), big.NewRat(1, denom))
+			term = term.Mul(factor)
+		}
+		result = result.Add(term)
+	}
+
+	return result, nil
+}
+
+// Degree of the polynomial, -1 for the zero polynomial
+func (p Polynomial) Degree() int {
+	return len(p.coeffs) - 1
+}
+
+// Copy of the coefficients, lowest power first
+func (p Polynomial) Coefficients() []*big.Rat {
+	coeffs := make([]*big.Rat, len(p.coeffs))
+	for i, c := range p.coeffs {
+		coeffs[i] = new(big.Rat).Set(c)
+	}
+	return coeffs
+}
+
+// Evaluates the polynomial with Horner's method
+func (p Polynomial) Eval(x *big.Rat) *big.Rat {
+	result := new(big.Rat)
+
+	for i := len(p.coeffs) - 1; i >= 0; i-- {
+		result.Mul(result, x)
+		result.Add(result, p.coeffs[i])
+	}
+
+	return result
+}
+
+func (p Polynomial) EvalInt(x int64) *big.Rat {
+	return p.Eval(new(big.Rat).SetInt64(x))
+}
+
+func (p Polynomial) Add(other Polynomial) Polynomial {
+	n := max(len(p.coeffs), len(other.coeffs))
+	coeffs := make([]*big.Rat, n)
+
+	for i := range coeffs {
+		coeffs[i] = new(big.Rat)
+		if i < len(p.coeffs) {
+			coeffs[i].Add(coeffs[i], p.coeffs[i])
+		}
+		if i < len(other.coeffs) {
+			coeffs[i].Add(coeffs[i], other.coeffs[i])
+		}
+	}
+
+	result := Polynomial{coeffs}
+	result.trim()
+	return result
+}
+
+func (p Polynomial) Mul(other Polynomial) Polynomial {
+	if len(p.coeffs) == 0 || len(other.coeffs) == 0 {
+		return Polynomial{}
+	}
+
+	coeffs := make([]*big.Rat, len(p.coeffs)+len(other.coeffs)-1)
+	for i := range coeffs {
+		coeffs[i] = new(big.Rat)
+	}
+	for i, a := range p.coeffs {
+		for j, b := range other.coeffs {
+			coeffs[i+j].Add(coeffs[i+j], new(big.Rat).Mul(a, b))
+		}
+	}
+
+	result := Polynomial{coeffs}
+	result.trim()
+	return result
+}
+
+func (p Polynomial) Scale(factor *big.Rat) Polynomial {
+	coeffs := make([]*big.Rat, len(p.coeffs))
+	for i, c := range p.coeffs {
+		coeffs[i] = new(big.Rat).Mul(c, factor)
+	}
+
+	result := Polynomial{coeffs}
+	result.trim()
+	return result
+}
+
+func (p Polynomial) String() string {
+	if len(p.coeffs) == 0 {
+		return "0"
+	}
+
+	terms := []string{}
+	for i := len(p.coeffs) - 1; i >= 0; i-- {
+		c := p.coeffs[i]
+		if c.Sign() == 0 {
+			continue
+		}
+		switch i {
+		case 0:
+			terms = append(terms, c.RatString())
+		case 1:
+			terms = append(terms, fmt.Sprintf("%s*x", c.RatString()))
+		default:
+			terms = append(terms, fmt.Sprintf("%s*x^%d", c.RatString(), i))
+		}
+	}
+
+	return strings.ReplaceAll(strings.Join(terms, " + "), "+ -", "- ")
+}
+
+// Drops zero leading coefficients so Degree is exact
+func (p *Polynomial) trim() {
+	for len(p.coeffs) > 0 && p.coeffs[len(p.coeffs)-1].Sign() == 0 {
+		p.coeffs = p.coeffs[:len(p.coeffs)-1]
+	}
+}
+
+func allZero(row []*big.Rat) bool {
+	for _, val := range row {
+		if val.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
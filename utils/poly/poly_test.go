@@ -0,0 +1,125 @@
+package poly
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func rats(values ...int64) []*big.Rat {
+	result := make([]*big.Rat, len(values))
+	for i, v := range values {
+		result[i] = big.NewRat(v, 1)
+	}
+	return result
+}
+
+func sameCoefficients(p Polynomial, want []*big.Rat) bool {
+	got := p.Coefficients()
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i].Cmp(want[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFitSequence(t *testing.T) {
+	tests := []struct {
+		name   string
+		ys     []int
+		coeffs []*big.Rat
+		degree int
+		str    string
+	}{
+		{"quadratic", []int{1, 0, 3, 10, 21}, rats(1, -3, 2), 2, "2*x^2 - 3*x + 1"},
+		{"triangular numbers", []int{0, 1, 3, 6, 10}, []*big.Rat{big.NewRat(0, 1), big.NewRat(1, 2), big.NewRat(1, 2)}, 2, "1/2*x^2 + 1/2*x"},
+		{"constant", []int{7, 7, 7}, rats(7), 0, "7"},
+		{"zeros", []int{0, 0, 0}, nil, -1, "0"},
+		{"empty", nil, nil, -1, "0"},
+		{"single sample", []int{-4}, rats(-4), 0, "-4"},
+	}
+
+	for _, tt := range tests {
+		p := FitSequence(tt.ys)
+		if !sameCoefficients(p, tt.coeffs) {
+			t.Errorf("%v: FitSequence(%v) = %v, want coefficients %v", tt.name, tt.ys, p, tt.coeffs)
+		}
+		if p.Degree() != tt.degree {
+			t.Errorf("%v: Degree() = %v, want %v", tt.name, p.Degree(), tt.degree)
+		}
+		if p.String() != tt.str {
+			t.Errorf("%v: String() = %q, want %q", tt.name, p.String(), tt.str)
+		}
+
+		// the fit passes through every sample
+		for x, y := range tt.ys {
+			if got := p.EvalInt(int64(x)); got.Cmp(big.NewRat(int64(y), 1)) != 0 {
+				t.Errorf("%v: EvalInt(%v) = %v, want %v", tt.name, x, got.RatString(), y)
+			}
+		}
+	}
+}
+
+func TestLagrange(t *testing.T) {
+	// p(x) = 2x^2 - 3x + 1 sampled at points out of order, so some
+	// denominators xs[i] - xs[j] are negative
+	xs := []int{3, -2, 1}
+	ys := []int{10, 15, 0}
+
+	p, err := Lagrange(xs, ys)
+	if err != nil {
+		t.Fatalf("Lagrange() error = %v", err)
+	}
+	if !sameCoefficients(p, rats(1, -3, 2)) {
+		t.Errorf("Lagrange() = %v, want 2*x^2 - 3*x + 1", p)
+	}
+	// far outside int64 once squared
+	x := big.NewInt(1_000_000_000_000)
+	want := new(big.Int).Mul(x, x)
+	want.Mul(want, big.NewInt(2)).Sub(want, new(big.Int).Mul(x, big.NewInt(3))).Add(want, big.NewInt(1))
+	if got := p.EvalInt(x.Int64()); got.Cmp(new(big.Rat).SetInt(want)) != 0 {
+		t.Errorf("EvalInt(%v) = %v, want %v", x, got.RatString(), want)
+	}
+
+	if _, err := Lagrange([]int{1, 1}, []int{2, 3}); !errors.Is(err, ErrDuplicateX) {
+		t.Errorf("Lagrange() with duplicate x error = %v, want ErrDuplicateX", err)
+	}
+	if _, err := Lagrange([]int{1, 2}, []int{2}); err == nil {
+		t.Errorf("Lagrange() with mismatched lengths succeeded")
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	x := New(rats(0, 1)...)
+	one := New(rats(1)...)
+	minusOne := one.Scale(big.NewRat(-1, 1))
+
+	tests := []struct {
+		name   string
+		got    Polynomial
+		coeffs []*big.Rat
+		str    string
+	}{
+		{"(x+1)(x-1)", x.Add(one).Mul(x.Add(minusOne)), rats(-1, 0, 1), "1*x^2 - 1"},
+		{"cancelling leading terms", x.Mul(x).Add(x).Add(x.Mul(x).Scale(big.NewRat(-1, 1))), rats(0, 1), "1*x"},
+		{"scale by zero", x.Add(one).Scale(new(big.Rat)), nil, "0"},
+		{"times zero", x.Mul(Polynomial{}), nil, "0"},
+		{"leading zeros trimmed", New(rats(3, 0, 0)...), rats(3), "3"},
+	}
+
+	for _, tt := range tests {
+		if !sameCoefficients(tt.got, tt.coeffs) {
+			t.Errorf("%v = %v, want coefficients %v", tt.name, tt.got, tt.coeffs)
+		}
+		if tt.got.Degree() != len(tt.coeffs)-1 {
+			t.Errorf("%v: Degree() = %v, want %v", tt.name, tt.got.Degree(), len(tt.coeffs)-1)
+		}
+		if tt.got.String() != tt.str {
+			t.Errorf("%v: String() = %q, want %q", tt.name, tt.got.String(), tt.str)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+)
+
+var ErrOverflow = errors.New("card count overflows int")
+
+// Scores one card from how many of its numbers match
+type Scorable interface {
+	Score(matches int) int
+}
+
+// Scores a whole list of cards at once, for rules where cards affect each
+// other. matches[i] is the number of matches on card i+1
+type ListScorable interface {
+	ScoreAll(matches []int) ([]int, error)
+}
+
+// Scores every card of a list on its own
+func Each(s Scorable) ListScorable {
+	return each{s}
+}
+
+type each struct {
+	Scorable
+}
+
+func (e each) ScoreAll(matches []int) ([]int, error) {
+	scores := make([]int, len(matches))
+	for i, count := range matches {
+		scores[i] = e.Score(count)
+	}
+	return scores, nil
+}
+
+// Doubling: the first match is worth one point and every further match
+// multiplies the score by Base
+type Doubling struct {
+	Base int
+}
+
+func (s Doubling) Score(matches int) int {
+	if matches == 0 {
+		return 0
+	}
+
+	score := 1
+	for i := 1; i < matches; i++ {
+		score *= s.Base
+	}
+	return score
+}
+
+// Linear: every match is worth the same number of points
+type Linear struct {
+	PointsPerMatch int
+}
+
+func (s Linear) Score(matches int) int {
+	return matches * s.PointsPerMatch
+}
+
+// Copy cascade: a card with n matches wins a copy of each of the next n
+// cards. Scores the cards produced by each original card, so the sum over
+// all cards is the total number of cards.
+type Copies struct{}
+
+func (Copies) ScoreAll(matches []int) ([]int, error) {
+	table, err := NewCopyTable(matches)
+	if err != nil {
+		return nil, err
+	}
+	return table.Produced(), nil
+}
+
+// Adds up scores, failing instead of wrapping around
+func Sum(scores []int) (int, error) {
+	var sum int
+
+	for _, score := range scores {
+		if score > 0 && sum > math.MaxInt-score {
+			return 0, ErrOverflow
+		}
+		sum += score
+	}
+
+	return sum, nil
+}
+
+// Copy cascade for a fixed list of cards, index 0 holds card 1
+type CopyTable struct {
+	matches []int
+	// cards produced by one copy of each card, the card itself included
+	produced []int
+	total    int
+}
+
+// Fills the table from the last card backwards: a copy of card i yields
+// itself plus everything produced by the next matches[i] cards. Counts can
+// double with every card, ErrOverflow is returned once they stop fitting
+func NewCopyTable(matches []int) (*CopyTable, error) {
+	n := len(matches)
+	t := &CopyTable{matches: matches, produced: make([]int, n)}
+
+	for i := n - 1; i >= 0; i-- {
+		if i+matches[i] >= n && matches[i] > 0 {
+			return nil, fmt.Errorf("card %d has %d matches but only %d cards follow it", i+1, matches[i], n-i-1)
+		}
+		produced, err := Sum(t.produced[i+1 : i+1+matches[i]])
+		if err != nil || produced == math.MaxInt {
+			return nil, fmt.Errorf("card %d: %w", i+1, ErrOverflow)
+		}
+		t.produced[i] = produced + 1
+	}
+
+	// every card held is counted once in the total, so copies of a single
+	// card never exceed it either
+	total, err := Sum(t.produced)
+	if err != nil {
+		return nil, err
+	}
+	t.total = total
+
+	slog.Debug("Built copy table", "produced", t.produced)
+	return t, nil
+}
+
+// Cards produced by one copy of each card
+func (t *CopyTable) Produced() []int {
+	produced := make([]int, len(t.produced))
+	copy(produced, t.produced)
+	return produced
+}
+
+func (t *CopyTable) Total() int {
+	return t.total
+}
+
+// How many copies of each card are held once the cascade is over
+func (t *CopyTable) Copies() []int {
+	copies := make([]int, len(t.matches))
+
+	for i := range copies {
+		copies[i] += 1
+		for j := i + 1; j <= i+t.matches[i]; j++ {
+			copies[j] += copies[i]
+		}
+	}
+
+	return copies
+}
@@ -0,0 +1,73 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+// matches on the cards of the puzzle example
+var example = []int{4, 2, 2, 1, 0, 0}
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy ListScorable
+		want     []int
+	}{
+		{"doubling", Each(Doubling{Base: 2}), []int{8, 2, 2, 1, 0, 0}},
+		{"tripling", Each(Doubling{Base: 3}), []int{27, 3, 3, 1, 0, 0}},
+		{"linear", Each(Linear{PointsPerMatch: 2}), []int{8, 4, 4, 2, 0, 0}},
+		{"copies", Copies{}, []int{15, 7, 4, 2, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.strategy.ScoreAll(example)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("%v: ScoreAll() = %v %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestCopyTable(t *testing.T) {
+	table, err := NewCopyTable(example)
+	if err != nil {
+		t.Fatalf("NewCopyTable() error = %v", err)
+	}
+	if table.Total() != 30 {
+		t.Errorf("Total() = %v, want 30", table.Total())
+	}
+	if want := []int{1, 2, 4, 8, 14, 1}; !slices.Equal(table.Copies(), want) {
+		t.Errorf("Copies() = %v, want %v", table.Copies(), want)
+	}
+
+	if _, err := NewCopyTable([]int{1, 2, 0}); err == nil {
+		t.Errorf("NewCopyTable() with matches past the last card succeeded")
+	}
+}
+
+func TestCopyTableOverflow(t *testing.T) {
+	// every card wins a copy of all the cards after it, doubling the count
+	// per card
+	matches := make([]int, 70)
+	for i := range matches {
+		matches[i] = len(matches) - i - 1
+	}
+
+	if _, err := NewCopyTable(matches); !errors.Is(err, ErrOverflow) {
+		t.Errorf("NewCopyTable() error = %v, want ErrOverflow", err)
+	}
+	if _, err := NewCopyTable(matches[len(matches)-60:]); err != nil {
+		t.Errorf("NewCopyTable() of 60 cards error = %v", err)
+	}
+}
+
+func TestSum(t *testing.T) {
+	if sum, err := Sum([]int{1, 2, 3}); sum != 6 || err != nil {
+		t.Errorf("Sum() = %v %v, want 6", sum, err)
+	}
+	if _, err := Sum([]int{math.MaxInt, 1}); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Sum() error = %v, want ErrOverflow", err)
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Block of consecutive non-blank lines
+type Section struct {
+	Header string
+	Lines  []string
+	// 1-based line number of the first line of the block (the header if present)
+	Start int
+}
+
+// Header without its trailing colon, e.g. "seed-to-soil map"
+func (s Section) Title() string {
+	return strings.TrimSuffix(strings.TrimSpace(s.Header), ":")
+}
+
+// 1-based line number in the input of Lines[i]
+func (s Section) LineNumber(i int) int {
+	if s.Header != "" {
+		return s.Start + i + 1
+	}
+	return s.Start + i
+}
+
+// Default header detection, matches lines such as "seed-to-soil map:"
+func ColonHeader(line string) bool {
+	return strings.HasSuffix(strings.TrimSpace(line), ":")
+}
+
+// Reads blank line separated blocks of input, in the style of bufio.Scanner
+type SectionScanner struct {
+	// Decides whether the first line of a block is a header. Set to nil to
+	// treat every line as content.
+	IsHeader func(line string) bool
+	// Strip surrounding whitespace from every line
+	Trim bool
+
+	scanner *bufio.Scanner
+	lineNum int
+	section Section
+}
+
+func NewSectionScanner(r io.Reader) *SectionScanner {
+	s := &SectionScanner{
+		IsHeader: ColonHeader,
+		scanner:  bufio.NewScanner(r),
+	}
+	return s
+}
+
+// Advances to the next block, returning false at the end of input
+func (s *SectionScanner) Scan() bool {
+	s.section = Section{}
+
+	for s.scanner.Scan() {
+		s.lineNum++
+		line := s.scanner.Text()
+
+		if strings.TrimSpace(line) == "" {
+			if s.section.Start != 0 {
+				return true
+			}
+			continue
+		}
+
+		if s.Trim {
+			line = strings.TrimSpace(line)
+		}
+
+		if s.section.Start == 0 {
+			s.section.Start = s.lineNum
+			if s.IsHeader != nil && s.IsHeader(line) {
+				s.section.Header = line
+				continue
+			}
+		}
+		s.section.Lines = append(s.section.Lines, line)
+	}
+
+	return s.section.Start != 0
+}
+
+func (s *SectionScanner) Section() Section {
+	return s.section
+}
+
+func (s *SectionScanner) Err() error {
+	return s.scanner.Err()
+}
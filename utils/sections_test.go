@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func scanSections(s *SectionScanner) []Section {
+	sections := []Section{}
+	for s.Scan() {
+		sections = append(sections, s.Section())
+	}
+	return sections
+}
+
+func TestSectionScanner(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		isHeader func(string) bool
+		trim     bool
+		want     []Section
+	}{
+		{
+			"headers", "seeds: 79 14\n\nseed-to-soil map:\n50 98 2\n52 50 48\n", ColonHeader, false,
+			[]Section{
+				{"", []string{"seeds: 79 14"}, 1},
+				{"seed-to-soil map:", []string{"50 98 2", "52 50 48"}, 3},
+			},
+		},
+		{
+			"no header detection", "a:\nb\n\nc:\n", nil, false,
+			[]Section{{"", []string{"a:", "b"}, 1}, {"", []string{"c:"}, 4}},
+		},
+		{
+			"header only", "first:\n\nsecond:\nx\n", ColonHeader, false,
+			[]Section{{"first:", nil, 1}, {"second:", []string{"x"}, 3}},
+		},
+		{
+			"repeated blank lines", "\n\n a\n\n \n\t\nb\n\n\n", nil, false,
+			[]Section{{"", []string{" a"}, 3}, {"", []string{"b"}, 7}},
+		},
+		{
+			"trim", "  head:  \n  1 2 \n", ColonHeader, true,
+			[]Section{{"head:", []string{"1 2"}, 1}},
+		},
+		{"empty", "\n\n", ColonHeader, false, []Section{}},
+	}
+
+	for _, tt := range tests {
+		s := NewSectionScanner(strings.NewReader(tt.input))
+		s.IsHeader = tt.isHeader
+		s.Trim = tt.trim
+
+		if got := scanSections(s); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: sections = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSectionLineNumber(t *testing.T) {
+	s := NewSectionScanner(strings.NewReader("\nnumbers:\n1\n2\n\n\n3\n4\n"))
+	sections := scanSections(s)
+	if len(sections) != 2 {
+		t.Fatalf("sections = %+v, want 2", sections)
+	}
+
+	tests := []struct {
+		section Section
+		i       int
+		want    int
+	}{
+		// the header is line 2
+		{sections[0], 0, 3},
+		{sections[0], 1, 4},
+		{sections[1], 0, 7},
+		{sections[1], 1, 8},
+	}
+
+	for _, tt := range tests {
+		if got := tt.section.LineNumber(tt.i); got != tt.want {
+			t.Errorf("%q LineNumber(%v) = %v, want %v", tt.section.Lines, tt.i, got, tt.want)
+		}
+	}
+
+	if title := sections[0].Title(); title != "numbers" {
+		t.Errorf("Title() = %q, want numbers", title)
+	}
+}
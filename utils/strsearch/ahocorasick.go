@@ -0,0 +1,254 @@
+package strsearch
+
+import (
+	"sort"
+)
+
+// Alphabet an Automaton can be built over
+type Symbol interface {
+	~byte | ~rune
+}
+
+// Occurrence of a dictionary entry, Start and End index the searched text
+// with End exclusive
+type Match struct {
+	Pattern int
+	Start   int
+	End     int
+}
+
+type node[S Symbol] struct {
+	children map[S]int
+	fail     int
+	// patterns ending at this node, including those reached through fail links
+	out []int
+}
+
+// Aho-Corasick automaton over a fixed dictionary. It finds every, possibly
+// overlapping, occurrence of the dictionary in a single pass over the text.
+type Automaton[S Symbol] struct {
+	patterns [][]S
+	maxLen   int
+	forward  []node[S]
+	// dictionary with every entry reversed, used to find the last match
+	// by scanning backwards
+	backward []node[S]
+}
+
+func New[S Symbol](patterns ...[]S) *Automaton[S] {
+	a := &Automaton[S]{patterns: patterns}
+
+	reversed := make([][]S, len(patterns))
+	for i, pattern := range patterns {
+		a.maxLen = max(a.maxLen, len(pattern))
+		reversed[i] = make([]S, len(pattern))
+		for j := range pattern {
+			reversed[i][len(pattern)-1-j] = pattern[j]
+		}
+	}
+
+	a.forward = build(patterns)
+	a.backward = build(reversed)
+	return a
+}
+
+// Builds an automaton that searches []byte, offsets are byte offsets
+func NewBytes(patterns ...string) *Automaton[byte] {
+	converted := make([][]byte, len(patterns))
+	for i, pattern := range patterns {
+		converted[i] = []byte(pattern)
+	}
+	return New(converted...)
+}
+
+// Builds an automaton that searches []rune, offsets are rune indices
+func NewRunes(patterns ...string) *Automaton[rune] {
+	converted := make([][]rune, len(patterns))
+	for i, pattern := range patterns {
+		converted[i] = []rune(pattern)
+	}
+	return New(converted...)
+}
+
+func (a *Automaton[S]) Len() int {
+	return len(a.patterns)
+}
+
+func (a *Automaton[S]) Pattern(i int) []S {
+	return a.patterns[i]
+}
+
+// Calls fn for every match in order of its end position. Returning false
+// from fn stops the scan.
+func (a *Automaton[S]) Scan(text []S, fn func(Match) bool) {
+	c := a.Cursor()
+
+	for _, sym := range text {
+		for _, match := range c.Next(sym) {
+			if !fn(match) {
+				return
+			}
+		}
+	}
+}
+
+func (a *Automaton[S]) FindAll(text []S) []Match {
+	matches := []Match{}
+
+	a.Scan(text, func(m Match) bool {
+		matches = append(matches, m)
+		return true
+	})
+
+	return matches
+}
+
+// Returns the match that starts earliest, preferring the longest on ties.
+// Scanning stops as soon as no later match could start before it.
+func (a *Automaton[S]) First(text []S) (Match, bool) {
+	var best Match
+	found := false
+
+	c := a.Cursor()
+	for pos, sym := range text {
+		if found && pos-a.maxLen+1 > best.Start {
+			break
+		}
+		for _, match := range c.Next(sym) {
+			if !found || match.Start < best.Start || (match.Start == best.Start && match.End > best.End) {
+				best = match
+				found = true
+			}
+		}
+	}
+
+	return best, found
+}
+
+// Returns the match that starts latest, preferring the longest on ties.
+// The text is scanned backwards so only its tail is read.
+func (a *Automaton[S]) Last(text []S) (Match, bool) {
+	state := 0
+
+	for i := len(text) - 1; i >= 0; i-- {
+		state = step(a.backward, state, text[i])
+		outputs := a.backward[state].out
+		if len(outputs) == 0 {
+			continue
+		}
+
+		// the reversed match starting here is the original match ending here
+		best := Match{Pattern: -1, Start: i}
+		for _, pattern := range outputs {
+			end := i + len(a.patterns[pattern])
+			if best.Pattern < 0 || end > best.End {
+				best.Pattern = pattern
+				best.End = end
+			}
+		}
+		return best, true
+	}
+
+	return Match{}, false
+}
+
+// Incremental matcher for text that arrives one symbol at a time
+type Cursor[S Symbol] struct {
+	automaton *Automaton[S]
+	state     int
+	pos       int
+}
+
+func (a *Automaton[S]) Cursor() *Cursor[S] {
+	return &Cursor[S]{automaton: a}
+}
+
+// Consumes one symbol and returns the matches that end with it
+func (c *Cursor[S]) Next(sym S) []Match {
+	c.state = step(c.automaton.forward, c.state, sym)
+	c.pos++
+
+	outputs := c.automaton.forward[c.state].out
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	matches := make([]Match, len(outputs))
+	for i, pattern := range outputs {
+		length := len(c.automaton.patterns[pattern])
+		matches[i] = Match{Pattern: pattern, Start: c.pos - length, End: c.pos}
+	}
+	return matches
+}
+
+func step[S Symbol](nodes []node[S], state int, sym S) int {
+	for {
+		if next, ok := nodes[state].children[sym]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = nodes[state].fail
+	}
+}
+
+func build[S Symbol](patterns [][]S) []node[S] {
+	nodes := []node[S]{{children: make(map[S]int)}}
+
+	for i, pattern := range patterns {
+		if len(pattern) == 0 {
+			continue
+		}
+		state := 0
+		for _, sym := range pattern {
+			next, ok := nodes[state].children[sym]
+			if !ok {
+				next = len(nodes)
+				nodes = append(nodes, node[S]{children: make(map[S]int)})
+				nodes[state].children[sym] = next
+			}
+			state = next
+		}
+		nodes[state].out = append(nodes[state].out, i)
+	}
+
+	// breadth first so fail targets are always finished before their users
+	queue := []int{}
+	for _, child := range sortedChildren(nodes[0].children) {
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for sym, child := range nodes[state].children {
+			fail := nodes[state].fail
+			for {
+				if next, ok := nodes[fail].children[sym]; ok && next != child {
+					fail = next
+					break
+				}
+				if fail == 0 {
+					break
+				}
+				fail = nodes[fail].fail
+			}
+			nodes[child].fail = fail
+			nodes[child].out = append(nodes[child].out, nodes[fail].out...)
+		}
+		queue = append(queue, sortedChildren(nodes[state].children)...)
+	}
+
+	return nodes
+}
+
+func sortedChildren[S Symbol](children map[S]int) []int {
+	states := make([]int, 0, len(children))
+	for _, state := range children {
+		states = append(states, state)
+	}
+	sort.Ints(states)
+	return states
+}
@@ -0,0 +1,94 @@
+package strsearch
+
+import (
+	"reflect"
+	"testing"
+)
+
+var digitWords = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+		want     []Match
+	}{
+		{
+			"textbook", []string{"he", "she", "his", "hers"}, "ushers",
+			[]Match{{1, 1, 4}, {0, 2, 4}, {3, 2, 6}},
+		},
+		{
+			"overlapping digits", digitWords, "eightwone",
+			[]Match{{7, 0, 5}, {1, 4, 7}, {0, 6, 9}},
+		},
+		{
+			"pattern inside pattern", []string{"aa", "a"}, "aaa",
+			[]Match{{1, 0, 1}, {0, 0, 2}, {1, 1, 2}, {0, 1, 3}, {1, 2, 3}},
+		},
+		{"empty pattern never matches", []string{"", "x"}, "axa", []Match{{1, 1, 2}}},
+		{"empty dictionary", nil, "abc", []Match{}},
+		{"no match", []string{"xyz"}, "xyxy", []Match{}},
+	}
+
+	for _, tt := range tests {
+		got := NewRunes(tt.patterns...).FindAll([]rune(tt.text))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: FindAll(%q) = %v, want %v", tt.name, tt.text, got, tt.want)
+		}
+
+		bytes := NewBytes(tt.patterns...).FindAll([]byte(tt.text))
+		if !reflect.DeepEqual(bytes, tt.want) {
+			t.Errorf("%v: byte FindAll(%q) = %v, want %v", tt.name, tt.text, bytes, tt.want)
+		}
+	}
+}
+
+func TestFirstLast(t *testing.T) {
+	tests := []struct {
+		name        string
+		patterns    []string
+		text        string
+		first, last Match
+		found       bool
+	}{
+		{"digits", digitWords, "eightwone", Match{7, 0, 5}, Match{0, 6, 9}, true},
+		{"digits in noise", digitWords, "xtwoneabc", Match{1, 1, 4}, Match{0, 3, 6}, true},
+		// ties on the start position go to the longest match
+		{"longest on ties", []string{"a", "ab", "abc", "bc"}, "xabc", Match{2, 1, 4}, Match{3, 2, 4}, true},
+		// "bc" is found first but "abcd" starts earlier and ends later
+		{"earlier start ends later", []string{"bc", "abcd"}, "abcd", Match{1, 0, 4}, Match{0, 1, 3}, true},
+		{"single symbol", []string{"x"}, "x", Match{0, 0, 1}, Match{0, 0, 1}, true},
+		{"no match", digitWords, "abc", Match{}, Match{}, false},
+		{"empty text", digitWords, "", Match{}, Match{}, false},
+		{"only empty pattern", []string{""}, "abc", Match{}, Match{}, false},
+	}
+
+	for _, tt := range tests {
+		a := NewRunes(tt.patterns...)
+
+		first, ok := a.First([]rune(tt.text))
+		if ok != tt.found || first != tt.first {
+			t.Errorf("%v: First(%q) = %v %v, want %v %v", tt.name, tt.text, first, ok, tt.first, tt.found)
+		}
+
+		last, ok := a.Last([]rune(tt.text))
+		if ok != tt.found || last != tt.last {
+			t.Errorf("%v: Last(%q) = %v %v, want %v %v", tt.name, tt.text, last, ok, tt.last, tt.found)
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	a := NewBytes("he", "she", "his", "hers")
+	c := a.Cursor()
+
+	got := []Match{}
+	for _, sym := range []byte("ushers") {
+		got = append(got, c.Next(sym)...)
+	}
+
+	if want := a.FindAll([]byte("ushers")); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cursor matches = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanpdenoux/advent-of-code/utils"
+)
+
+const watchInterval = 500 * time.Millisecond
+
+// Polls the day's source, examples and input, and rebuilds and reruns the
+// day whenever one of them changes until interrupted
+func watch(day int) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	dir, err := os.MkdirTemp("", "aoc-watch")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	binary := filepath.Join(dir, "aoc")
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	var seen map[string]time.Time
+	var previous map[string]string
+	for {
+		times := modTimes(watchedFiles(day))
+		if seen == nil || !maps.EqualFunc(seen, times, time.Time.Equal) {
+			seen = times
+			previous = rerun(ctx, binary, day, previous)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Source, input and example files of a day, examples are looked up on every
+// poll so new ones are picked up
+func watchedFiles(day int) []string {
+	files := []string{fmt.Sprintf("solutions/day%d.go", day), utils.BuildDataPath(day)}
+	return append(files, exampleFiles(day)...)
+}
+
+func exampleFiles(day int) []string {
+	files, _ := filepath.Glob(fmt.Sprintf("solutions/examples/day%d-*.txt", day))
+	return files
+}
+
+// Modification time of every file that exists
+func modTimes(files []string) map[string]time.Time {
+	times := map[string]time.Time{}
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			times[file] = info.ModTime()
+		}
+	}
+	return times
+}
+
+// Rebuilds the binary, solves the examples and then the input, and prints
+// how the answers moved since the previous run. Answers are keyed like
+// "example 2 part 1", a failed build keeps the previous answers
+func rerun(ctx context.Context, binary string, day int, previous map[string]string) map[string]string {
+	fmt.Printf("%s rerunning day %d\n", time.Now().Format(time.TimeOnly), day)
+
+	build := exec.CommandContext(ctx, "go", "build", "-o", binary, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		fmt.Printf("  build failed: %v\n%s", err, out)
+		return previous
+	}
+
+	current := map[string]string{}
+	order := []string{}
+	record := func(name string, result *dayResult) {
+		for _, answer := range result.Answers {
+			key := fmt.Sprintf("%s part %d", name, answer.Part)
+			current[key] = answer.Value
+			order = append(order, key)
+		}
+	}
+
+	checked, wrong := 0, 0
+	for n := 1; n <= len(exampleFiles(day)); n++ {
+		name := fmt.Sprintf("example %d", n)
+		result, err := runSolver(ctx, binary, day, "-example", strconv.Itoa(n))
+		if err != nil {
+			fmt.Printf("  %s failed: %v\n", name, err)
+			continue
+		}
+		record(name, result)
+
+		for _, answer := range result.Answers {
+			if answer.Expected == "" {
+				continue
+			}
+			checked++
+			if answer.Wrong() {
+				wrong++
+				fmt.Printf("  %s part %d: got %s, want %s\n", name, answer.Part, answer.Value, answer.Expected)
+			}
+		}
+	}
+	fmt.Printf("  examples: %d/%d answers right\n", checked-wrong, checked)
+
+	if _, err := os.Stat(utils.BuildDataPath(day)); err != nil {
+		fmt.Printf("  no input at %v\n", utils.BuildDataPath(day))
+	} else if result, err := runSolver(ctx, binary, day); err != nil {
+		fmt.Printf("  input failed: %v\n", err)
+	} else {
+		record("input", result)
+	}
+
+	printDiff(previous, current, order)
+	return current
+}
+
+// Runs the freshly built binary on one input and decodes its JSON result,
+// solver flags given to watch are passed on
+func runSolver(ctx context.Context, binary string, day int, args ...string) (*dayResult, error) {
+	args = append([]string{"run", "-day", strconv.Itoa(day), "-year", strconv.Itoa(*year), "-json", "-no-cache"}, args...)
+	if *skipCheck {
+		args = append(args, "-skip-check")
+	}
+	args = append(args, solverArgs()...)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, lastLine(stderr.String()))
+	}
+
+	result := &dayResult{}
+	if err := json.Unmarshal(stdout.Bytes(), result); err != nil {
+		return nil, fmt.Errorf("unreadable result: %v", err)
+	}
+	return result, nil
+}
+
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return lines[len(lines)-1]
+}
+
+// Prints answers that are new (+), changed (~) or gone (-) since the
+// previous run and counts the rest
+func printDiff(previous, current map[string]string, order []string) {
+	unchanged := 0
+
+	for _, key := range order {
+		old, ok := previous[key]
+		switch {
+		case !ok:
+			fmt.Printf("  + %s: %s\n", key, current[key])
+		case old != current[key]:
+			fmt.Printf("  ~ %s: %s -> %s\n", key, old, current[key])
+		default:
+			unchanged++
+		}
+	}
+
+	gone := []string{}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			gone = append(gone, key)
+		}
+	}
+	sort.Strings(gone)
+	for _, key := range gone {
+		fmt.Printf("  - %s: %s\n", key, previous[key])
+	}
+
+	if unchanged > 0 {
+		fmt.Printf("  %d unchanged\n", unchanged)
+	}
+}